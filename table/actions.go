@@ -0,0 +1,231 @@
+package table
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Action identifies one of the key bindings of the KeyMap.
+type Action int
+
+// Actions of the key bindings of the KeyMap.
+const (
+	ActionLineUp Action = iota
+	ActionLineDown
+	ActionPageUp
+	ActionPageDown
+	ActionHalfPageUp
+	ActionHalfPageDown
+	ActionGotoTop
+	ActionGotoBottom
+	ActionColumnLeft
+	ActionColumnRight
+	ActionToggleExpand
+	ActionNextMatch
+	ActionPrevMatch
+	ActionFilter
+	ActionClearFilter
+	ActionToggleSort
+	ActionCancelFilter
+	ActionAcceptFilter
+	ActionResetView
+	ActionRowHome
+	ActionRowEnd
+)
+
+// binding returns the key binding of the action, or nil for an unknown action.
+func (km *KeyMap) binding(a Action) *key.Binding {
+	switch a {
+	case ActionLineUp:
+		return &km.LineUp
+	case ActionLineDown:
+		return &km.LineDown
+	case ActionPageUp:
+		return &km.PageUp
+	case ActionPageDown:
+		return &km.PageDown
+	case ActionHalfPageUp:
+		return &km.HalfPageUp
+	case ActionHalfPageDown:
+		return &km.HalfPageDown
+	case ActionGotoTop:
+		return &km.GotoTop
+	case ActionGotoBottom:
+		return &km.GotoBottom
+	case ActionColumnLeft:
+		return &km.ColumnLeft
+	case ActionColumnRight:
+		return &km.ColumnRight
+	case ActionToggleExpand:
+		return &km.ToggleExpand
+	case ActionNextMatch:
+		return &km.NextMatch
+	case ActionPrevMatch:
+		return &km.PrevMatch
+	case ActionFilter:
+		return &km.Filter
+	case ActionClearFilter:
+		return &km.ClearFilter
+	case ActionToggleSort:
+		return &km.ToggleSort
+	case ActionCancelFilter:
+		return &km.CancelWhileFiltering
+	case ActionAcceptFilter:
+		return &km.AcceptWhileFiltering
+	case ActionResetView:
+		return &km.ResetView
+	case ActionRowHome:
+		return &km.RowHome
+	case ActionRowEnd:
+		return &km.RowEnd
+	default:
+		return nil
+	}
+}
+
+// keyActions are the actions run by key presses outside of the filter, in the
+// order their bindings are matched.
+var keyActions = []Action{
+	ActionFilter,
+	ActionClearFilter,
+	ActionLineUp,
+	ActionLineDown,
+	ActionPageUp,
+	ActionPageDown,
+	ActionHalfPageUp,
+	ActionHalfPageDown,
+	ActionGotoTop,
+	ActionGotoBottom,
+	ActionColumnLeft,
+	ActionColumnRight,
+	ActionRowHome,
+	ActionRowEnd,
+	ActionToggleExpand,
+	ActionNextMatch,
+	ActionPrevMatch,
+	ActionToggleSort,
+	ActionResetView,
+}
+
+// matchAction returns the action whose binding matches the key press, if any.
+func (km *KeyMap) matchAction(msg tea.KeyMsg) (Action, bool) {
+	for _, a := range keyActions {
+		if key.Matches(msg, *km.binding(a)) {
+			return a, true
+		}
+	}
+	return 0, false
+}
+
+// Dispatch runs an action as if the user had pressed its key binding, and
+// returns the command Update would return for the key press. Like key presses,
+// actions are ignored when the table is blurred or their binding is disabled,
+// and only ActionCancelFilter and ActionAcceptFilter run while the user is
+// editing the filter.
+func (m *Model) Dispatch(a Action) tea.Cmd {
+	km := m.activeKeyMap()
+	b := km.binding(a)
+	if !m.focus || b == nil || !b.Enabled() {
+		return nil
+	}
+	filtering := m.filterState == Filtering
+	if filtering && a != ActionCancelFilter && a != ActionAcceptFilter {
+		return nil
+	}
+
+	cursor, selected, start := m.cursor, m.SelectedIndex(), m.start
+	cmds := []tea.Cmd{m.runAction(a)}
+	cmds = append(cmds, m.changeCmds(cursor, selected, start)...)
+	if !filtering {
+		cmds = append(cmds, m.animateScroll(start))
+	}
+	return tea.Batch(cmds...)
+}
+
+// runAction runs the action of a key binding.
+func (m *Model) runAction(a Action) tea.Cmd {
+	switch a {
+	case ActionFilter:
+		return m.startFiltering()
+	case ActionClearFilter:
+		m.resetFiltering()
+	case ActionLineUp:
+		if m.cursor == 0 && m.wrapCursor {
+			m.SetCursor(m.numRows() - 1)
+		} else {
+			m.MoveUp(m.movementStep())
+		}
+	case ActionLineDown:
+		if m.cursor == m.numRows()-1 && m.wrapCursor {
+			m.SetCursor(0)
+		} else {
+			m.MoveDown(m.movementStep())
+		}
+	case ActionPageUp:
+		m.MoveUp(m.PageSize())
+	case ActionPageDown:
+		m.MoveDown(m.PageSize())
+	case ActionHalfPageUp:
+		m.MoveUp(m.PageSize() / 2) //nolint:mnd
+	case ActionHalfPageDown:
+		m.MoveDown(m.PageSize() / 2) //nolint:mnd
+	case ActionGotoTop:
+		m.GotoTop()
+	case ActionGotoBottom:
+		m.GotoBottom()
+	case ActionColumnLeft:
+		m.MoveLeft(1)
+	case ActionColumnRight:
+		m.MoveRight(1)
+	case ActionRowHome:
+		m.moveToEdgeColumn(false)
+	case ActionRowEnd:
+		m.moveToEdgeColumn(true)
+	case ActionToggleExpand:
+		m.ToggleExpanded(m.cursor)
+	case ActionNextMatch:
+		m.JumpToNextInColumn(m.jumpColumn, m.jumpFunc)
+	case ActionPrevMatch:
+		m.JumpToPrevInColumn(m.jumpColumn, m.jumpFunc)
+	case ActionToggleSort:
+		m.toggleSort(m.colCursor)
+	case ActionCancelFilter:
+		m.resetFiltering()
+	case ActionAcceptFilter:
+		m.acceptFilter()
+	case ActionResetView:
+		m.ResetView()
+	}
+	return nil
+}
+
+// SetActionEnabled enables or disables the key binding of an action, such as
+// ActionColumnRight in a table with a single column. Disabled bindings are
+// ignored by Update and hidden from the help. The paging actions are also
+// disabled while all the visible rows fit in the viewport. The action stays
+// disabled when the key map is replaced, until it is enabled again. Enabling
+// the binding of a feature that is turned off, such as ActionFilter without
+// filtering, has no effect until the feature is turned on.
+func (m *Model) SetActionEnabled(a Action, enabled bool) {
+	b := m.KeyMap.binding(a)
+	if b == nil {
+		return
+	}
+	if m.disabledActions == nil {
+		m.disabledActions = make(map[Action]bool)
+	}
+	if enabled {
+		delete(m.disabledActions, a)
+	} else {
+		m.disabledActions[a] = true
+	}
+	b.SetEnabled(enabled)
+	m.updateKeybindings()
+}
+
+// ActionEnabled returns whether the key binding of an action is enabled.
+func (m Model) ActionEnabled(a Action) bool {
+	km := m.activeKeyMap()
+	b := km.binding(a)
+	return b != nil && b.Enabled()
+}
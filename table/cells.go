@@ -0,0 +1,196 @@
+package table
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// cellRenderer renders the value of a cell given the width of its column.
+type cellRenderer func(value string, width int) string
+
+// partialBlocks are the glyphs of bars filled by 1/8 to 7/8.
+var partialBlocks = []rune("▏▎▍▌▋▊▉")
+
+const eighthsPerBlock = 8
+
+// WithBarColumn renders the numeric values of column col as bars filling the
+// column in proportion to maxValue, with values clamped to [0, maxValue].
+// Values that are not numbers render as they are. The stored rows are not
+// modified.
+func WithBarColumn(col int, maxValue float64, style lipgloss.Style) Option {
+	return func(m *Model) {
+		m.setCellRenderer(col, func(value string, width int) string {
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || maxValue <= 0 {
+				return value
+			}
+			ratio := math.Min(math.Max(v, 0), maxValue) / maxValue
+			eighths := int(math.Round(ratio * float64(width*eighthsPerBlock)))
+			bar := strings.Repeat("█", eighths/eighthsPerBlock)
+			if partial := eighths % eighthsPerBlock; partial > 0 {
+				bar += string(partialBlocks[partial-1])
+			}
+			return style.Render(bar)
+		})
+	}
+}
+
+// sparkLevels are the glyphs of a sparkline, from the lowest to the highest
+// value.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// WithSparklineColumn renders the values of column col, comma-separated
+// series of numbers like "1,3,2,5,4", as sparklines. When a series has more
+// values than the column is wide, its last values are shown. Values that are
+// not series of numbers render as they are. The stored rows are not modified.
+func WithSparklineColumn(col int) Option {
+	return func(m *Model) {
+		m.setCellRenderer(col, sparkline)
+	}
+}
+
+func sparkline(value string, width int) string {
+	fields := strings.Split(value, ",")
+	series := make([]float64, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return value
+		}
+		series[i] = v
+	}
+	if width > 0 && len(series) > width {
+		series = series[len(series)-width:]
+	}
+
+	low, high := series[0], series[0]
+	for _, v := range series {
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+	var b strings.Builder
+	for _, v := range series {
+		var level int
+		if high > low {
+			level = int(math.Round((v - low) / (high - low) * float64(len(sparkLevels)-1)))
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// WithTagColumn renders the values of column col, lists of tags separated by
+// sep like "go,rust,zig", as chips styled with style and padded by a space on
+// each side. When the chips overflow the column, the ones that fit are shown
+// followed by the number of the hidden ones, like "+2". The stored rows are not
+// modified.
+func WithTagColumn(col int, sep string, style lipgloss.Style) Option {
+	return func(m *Model) {
+		m.setCellRenderer(col, func(value string, width int) string {
+			return tags(value, sep, style.Padding(0, 1), width)
+		})
+	}
+}
+
+func tags(value, sep string, style lipgloss.Style, width int) string {
+	var chips []string
+	for _, tag := range strings.Split(value, sep) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			chips = append(chips, style.Render(tag))
+		}
+	}
+	for n := len(chips); n > 0; n-- {
+		line := strings.Join(chips[:n], " ")
+		if n < len(chips) {
+			line += " +" + strconv.Itoa(len(chips)-n)
+		}
+		if lipgloss.Width(line) <= width {
+			return line
+		}
+	}
+	if len(chips) == 0 {
+		return ""
+	}
+	return "+" + strconv.Itoa(len(chips))
+}
+
+// WithRelativeTimeColumn renders the values of column col, times formatted
+// with layout, relative to the time they are rendered at, like "2m ago" or
+// "in 3h". Values that do not parse render as they are. The stored rows are not
+// modified. See WithRelativeTimeRefresh to render them again as time passes.
+func WithRelativeTimeColumn(col int, layout string) Option {
+	return func(m *Model) {
+		m.setCellRenderer(col, func(value string, _ int) string {
+			t, err := time.Parse(layout, strings.TrimSpace(value))
+			if err != nil {
+				return value
+			}
+			return relativeTime(time.Since(t))
+		})
+	}
+}
+
+// relativeTime returns a short description of how long ago a time elapsed d
+// ago was, in its largest whole unit.
+func relativeTime(d time.Duration) string {
+	future := d < 0
+	if future {
+		d = -d
+	}
+	var s string
+	switch {
+	case d < time.Second:
+		return "now"
+	case d < time.Minute:
+		s = strconv.Itoa(int(d/time.Second)) + "s"
+	case d < time.Hour:
+		s = strconv.Itoa(int(d/time.Minute)) + "m"
+	case d < 24*time.Hour:
+		s = strconv.Itoa(int(d/time.Hour)) + "h"
+	default:
+		s = strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	}
+	if future {
+		return "in " + s
+	}
+	return s + " ago"
+}
+
+// relativeTimeMsg renders the relative times again.
+type relativeTimeMsg struct {
+	id int
+}
+
+// WithRelativeTimeRefresh sets how often the relative times of the columns set
+// with WithRelativeTimeColumn are rendered again. Start refreshing them by
+// running the command returned by RelativeTimeTick, for example in the Init
+// of the application; Update then returns the command of the next refresh.
+func WithRelativeTimeRefresh(interval time.Duration) Option {
+	return func(m *Model) {
+		m.relativeTimeRefresh = interval
+	}
+}
+
+// RelativeTimeTick returns the command rendering the relative times again
+// after the interval set with WithRelativeTimeRefresh, or nil if it is not
+// set.
+func (m Model) RelativeTimeTick() tea.Cmd {
+	if m.relativeTimeRefresh <= 0 {
+		return nil
+	}
+	id := m.id
+	return tea.Tick(m.relativeTimeRefresh, func(time.Time) tea.Msg {
+		return relativeTimeMsg{id: id}
+	})
+}
+
+func (m *Model) setCellRenderer(col int, renderer cellRenderer) {
+	if m.cellRenderers == nil {
+		m.cellRenderers = map[int]cellRenderer{}
+	}
+	m.cellRenderers[col] = renderer
+}
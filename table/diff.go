@@ -0,0 +1,68 @@
+package table
+
+// DiffRows compares oldRows and newRows, matching the rows by the key keyFn
+// returns for them, like the one set with WithRowKey, or by index if keyFn is
+// nil. It returns the indexes in newRows of the rows without a match in
+// oldRows, the indexes in oldRows of the rows without a match in newRows, and
+// the indexes in newRows of the rows whose values differ from the ones of
+// their match.
+func DiffRows(oldRows, newRows []Row, keyFn func(Row) string) (added, removed, changed []int) {
+	matches := matchRows(oldRows, newRows, keyFn)
+	matched := make([]bool, len(oldRows))
+	for i, p := range matches {
+		switch {
+		case p < 0:
+			added = append(added, i)
+		case !equalRows(oldRows[p], newRows[i]):
+			changed = append(changed, i)
+		}
+		if p >= 0 {
+			matched[p] = true
+		}
+	}
+	for i, ok := range matched {
+		if !ok {
+			removed = append(removed, i)
+		}
+	}
+	return added, removed, changed
+}
+
+// matchRows returns the index in oldRows of the row matching each row in
+// newRows, or -1 if there is none. Rows match by the key keyFn returns for
+// them, or by index if keyFn is nil.
+func matchRows(oldRows, newRows []Row, keyFn func(Row) string) []int {
+	matches := make([]int, len(newRows))
+	if keyFn == nil {
+		for i := range newRows {
+			matches[i] = -1
+			if i < len(oldRows) {
+				matches[i] = i
+			}
+		}
+		return matches
+	}
+	keys := make(map[string]int, len(oldRows))
+	for i, row := range oldRows {
+		keys[keyFn(row)] = i
+	}
+	for i, row := range newRows {
+		p, ok := keys[keyFn(row)]
+		if !ok {
+			p = -1
+		}
+		matches[i] = p
+	}
+	return matches
+}
+
+// equalRows reports whether two rows have the same values, missing values
+// being empty.
+func equalRows(a, b Row) bool {
+	for col := 0; col < max(len(a), len(b)); col++ {
+		if cellAt(a, col) != cellAt(b, col) {
+			return false
+		}
+	}
+	return true
+}
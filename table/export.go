@@ -0,0 +1,120 @@
+package table
+
+import (
+	"html"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// WriteMarkdown writes the table as a GitHub Flavored Markdown table: the
+// column titles, a delimiter row following the column alignments, then the
+// visible rows, in the order they are shown. Styles and ANSI escape sequences
+// are left out, pipes are escaped and line breaks are written as <br>.
+func (m Model) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	numColumns := m.numColumns()
+	writeRow := func(value func(col int) string) {
+		b.WriteString("|")
+		for col := 0; col < numColumns; col++ {
+			b.WriteString(" " + markdownEscape(value(col)) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(func(col int) string {
+		if col < len(m.cols) {
+			return m.cols[col].Title
+		}
+		return ""
+	})
+	writeRow(func(col int) string {
+		switch m.columnAlignment(col) {
+		case lipgloss.Right:
+			return "---:"
+		case lipgloss.Center:
+			return ":---:"
+		default:
+			return "---"
+		}
+	})
+	for i := 0; i < m.numRows(); i++ {
+		writeRow(func(col int) string {
+			return m.rawValue(i, col)
+		})
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHTML writes the table as an HTML table: a thead with the column titles
+// and a tbody with the visible rows, in the order they are shown. Cells that
+// are not left aligned have a text-align inline style. Styles and ANSI escape
+// sequences are left out, values are escaped and line breaks are written as
+// <br>.
+func (m Model) WriteHTML(w io.Writer) error {
+	var b strings.Builder
+	numColumns := m.numColumns()
+	writeRow := func(tag string, value func(col int) string) {
+		b.WriteString("    <tr>\n")
+		for col := 0; col < numColumns; col++ {
+			b.WriteString("      <" + tag + htmlAlignment(m.columnAlignment(col)) + ">")
+			b.WriteString(htmlEscape(value(col)))
+			b.WriteString("</" + tag + ">\n")
+		}
+		b.WriteString("    </tr>\n")
+	}
+
+	b.WriteString("<table>\n")
+	if len(m.cols) > 0 {
+		b.WriteString("  <thead>\n")
+		writeRow("th", func(col int) string {
+			return m.cols[col].Title
+		})
+		b.WriteString("  </thead>\n")
+	}
+	b.WriteString("  <tbody>\n")
+	for i := 0; i < m.numRows(); i++ {
+		writeRow("td", func(col int) string {
+			return m.rawValue(i, col)
+		})
+	}
+	b.WriteString("  </tbody>\n</table>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// htmlAlignment returns the style attribute aligning a cell, if needed.
+func htmlAlignment(pos lipgloss.Position) string {
+	switch pos {
+	case lipgloss.Right:
+		return ` style="text-align: right"`
+	case lipgloss.Center:
+		return ` style="text-align: center"`
+	default:
+		return ""
+	}
+}
+
+var htmlLineBreakReplacer = strings.NewReplacer("\r\n", "<br>", "\n", "<br>")
+
+// htmlEscape returns the value as the content of an HTML table cell.
+func htmlEscape(value string) string {
+	return htmlLineBreakReplacer.Replace(html.EscapeString(ansi.Strip(value)))
+}
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+// markdownEscape returns the value as the content of a Markdown table cell.
+func markdownEscape(value string) string {
+	return markdownReplacer.Replace(ansi.Strip(value))
+}
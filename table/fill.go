@@ -0,0 +1,93 @@
+package table
+
+// FillMode describes how the columns are widened when they are narrower than
+// the width of the table.
+type FillMode struct {
+	kind   fillKind
+	column int
+}
+
+type fillKind int
+
+const (
+	fillDefault fillKind = iota
+	fillRight
+	fillLeft
+	fillColumn
+	fillProportional
+)
+
+// Possible fill modes. The zero value lets the table spread the slack over the
+// narrowest columns.
+var (
+	// FillRight widens the last rendered column.
+	FillRight = FillMode{kind: fillRight}
+	// FillLeft widens the first rendered column.
+	FillLeft = FillMode{kind: fillLeft}
+	// FillProportional widens the rendered columns in proportion to their
+	// widths.
+	FillProportional = FillMode{kind: fillProportional}
+)
+
+// FillColumn widens column col, when it is rendered.
+func FillColumn(col int) FillMode {
+	return FillMode{kind: fillColumn, column: col}
+}
+
+// WithWidthFill sets how the columns are widened when they are narrower than
+// the width set with WithWidth.
+func WithWidthFill(mode FillMode) Option {
+	return func(m *Model) {
+		m.widthFill = mode
+	}
+}
+
+// fillWidth widens the given rendered columns in maxColumnWidths to the width
+// of the table, according to the fill mode.
+func (m Model) fillWidth(maxColumnWidths []int, columns []int) {
+	if m.widthFill.kind == fillDefault || m.manualWidth == 0 {
+		return
+	}
+	slack := m.manualWidth - m.columnsWidth(columns, maxColumnWidths)
+	if slack <= 0 {
+		return
+	}
+	var data []int
+	for _, c := range columns {
+		if c >= 0 {
+			data = append(data, c)
+		}
+	}
+	if len(data) == 0 {
+		return
+	}
+
+	switch m.widthFill.kind {
+	case fillRight:
+		maxColumnWidths[data[len(data)-1]] += slack
+	case fillLeft:
+		maxColumnWidths[data[0]] += slack
+	case fillColumn:
+		for _, c := range data {
+			if c == m.widthFill.column {
+				maxColumnWidths[c] += slack
+			}
+		}
+	case fillProportional:
+		var total int
+		for _, c := range data {
+			total += maxColumnWidths[c]
+		}
+		if total == 0 {
+			maxColumnWidths[data[len(data)-1]] += slack
+			return
+		}
+		remaining := slack
+		for _, c := range data {
+			extra := slack * maxColumnWidths[c] / total
+			maxColumnWidths[c] += extra
+			remaining -= extra
+		}
+		maxColumnWidths[data[len(data)-1]] += remaining
+	}
+}
@@ -0,0 +1,330 @@
+package table
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FilterState describes the current filtering state on the model.
+type FilterState int
+
+// Possible filter states.
+const (
+	Unfiltered    FilterState = iota // no filter set
+	Filtering                        // user is actively setting a filter
+	FilterApplied                    // a filter is applied and user is not editing filter
+)
+
+// String returns a human-readable string of the current filter state.
+func (f FilterState) String() string {
+	return [...]string{
+		"unfiltered",
+		"filtering",
+		"filter applied",
+	}[f]
+}
+
+// WithFiltering enables or disables filtering. When enabled, the Filter key
+// binding lets the user type a query and only the rows containing it are
+// shown. Filtering is disabled by default.
+func WithFiltering(enabled bool) Option {
+	return func(m *Model) {
+		m.filteringEnabled = enabled
+		if !enabled {
+			m.resetFiltering()
+		}
+		m.updateKeybindings()
+	}
+}
+
+// FilterCursorPolicy describes where the cursor moves when the selected row
+// stops matching the filter.
+type FilterCursorPolicy int
+
+// Possible filter cursor policies.
+const (
+	FilterCursorTop     FilterCursorPolicy = iota // to the first matching row
+	FilterCursorNearest                           // to the closest matching row
+)
+
+// WithFilterCursorPolicy sets where the cursor moves when the selected row
+// stops matching the filter: to the first matching row by default, or to the
+// matching row closest to it. Without matching rows, the cursor moves to the
+// top.
+func WithFilterCursorPolicy(policy FilterCursorPolicy) Option {
+	return func(m *Model) {
+		m.filterCursorPolicy = policy
+	}
+}
+
+// WithFilterMatcher sets the function reporting whether a row matches the
+// filter query. By default, rows match when any of their columns contains the
+// query, ignoring case. nil restores the default.
+func WithFilterMatcher(matcher func(row Row, query string) bool) Option {
+	return func(m *Model) {
+		m.filterMatcher = matcher
+		m.refilter()
+	}
+}
+
+// FilterColumns restricts the columns the default filter matcher searches for
+// the query to the given ones. Without columns, all the columns are searched.
+func FilterColumns(cols ...int) Option {
+	return func(m *Model) {
+		m.filterColumns = nil
+		if len(cols) > 0 {
+			m.filterColumns = cols
+		}
+		m.refilter()
+	}
+}
+
+// WithFilterDebounce sets how long the rows wait to be filtered after the
+// query changes, so that typing quickly or setting queries in a row with
+// SetFilterQuery filters them once, with the last query. Update returns the
+// commands filtering the rows. 0, the default, filters them at once.
+func WithFilterDebounce(d time.Duration) Option {
+	return func(m *Model) {
+		m.filterDebounce = d
+	}
+}
+
+// filterDebounceMsg filters the rows once the query stopped changing.
+type filterDebounceMsg struct {
+	id  int
+	tag int
+}
+
+// debounceFilter schedules the filtering of the rows with the current query,
+// cancelling the one scheduled before.
+func (m *Model) debounceFilter() tea.Cmd {
+	m.filterTag++
+	id, tag := m.id, m.filterTag
+	return tea.Tick(m.filterDebounce, func(time.Time) tea.Msg {
+		return filterDebounceMsg{id: id, tag: tag}
+	})
+}
+
+// debouncedFilter filters the rows if the query did not change since the
+// message was scheduled.
+func (m *Model) debouncedFilter(msg filterDebounceMsg) {
+	if msg.id != m.id || msg.tag != m.filterTag {
+		return
+	}
+	m.setFilterQuery(m.FilterInput.Value())
+}
+
+// refilter filters the rows again after the filter configuration changed.
+func (m *Model) refilter() {
+	if m.filterState != Unfiltered {
+		m.applyFilter()
+	}
+}
+
+// SetFilteringEnabled enables or disables filtering.
+func (m *Model) SetFilteringEnabled(enabled bool) {
+	WithFiltering(enabled)(m)
+}
+
+// FilteringEnabled returns whether or not filtering is enabled.
+func (m Model) FilteringEnabled() bool {
+	return m.filteringEnabled
+}
+
+// FilterState returns the current filter state.
+func (m Model) FilterState() FilterState {
+	return m.filterState
+}
+
+// SettingFilter returns whether or not the user is currently editing the
+// filter value.
+func (m Model) SettingFilter() bool {
+	return m.filterState == Filtering
+}
+
+// IsFiltered returns whether or not the rows are currently filtered.
+func (m Model) IsFiltered() bool {
+	return m.filterState == FilterApplied
+}
+
+// ResetFilter clears the filter and shows all rows again. The cursor stays on
+// the selected row.
+func (m *Model) ResetFilter() {
+	m.resetFiltering()
+}
+
+// FilterQuery returns the query of the filter, or an empty string if the rows
+// are not filtered.
+func (m Model) FilterQuery() string {
+	if m.filterState == Unfiltered {
+		return ""
+	}
+	return m.FilterInput.Value()
+}
+
+// SetFilterQuery filters the rows with the given query, as if the user had
+// typed and accepted it. An empty query clears the filter. If the user is
+// editing the filter, the query replaces the one being typed. The rows are
+// filtered at once, or with WithFilterDebounce, by the returned command once
+// the query stopped changing.
+func (m *Model) SetFilterQuery(query string) tea.Cmd {
+	if m.filterDebounce <= 0 {
+		m.setFilterQuery(query)
+		return nil
+	}
+	m.FilterInput.SetValue(query)
+	m.updateKeybindings()
+	return m.debounceFilter()
+}
+
+// setFilterQuery filters the rows with the given query at once.
+func (m *Model) setFilterQuery(query string) {
+	if query == "" && m.filterState != Filtering {
+		m.resetFiltering()
+		return
+	}
+	m.FilterInput.SetValue(query)
+	if m.filterState == Unfiltered {
+		m.filterState = FilterApplied
+	}
+	m.applyFilter()
+	m.updateKeybindings()
+}
+
+func (m *Model) handleFiltering(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		for _, a := range []Action{ActionCancelFilter, ActionAcceptFilter} {
+			if key.Matches(msg, *m.KeyMap.binding(a)) {
+				return m.runAction(a)
+			}
+		}
+	}
+
+	// Update the filter text input component
+	newFilterInputModel, cmd := m.FilterInput.Update(msg)
+	filterChanged := m.FilterInput.Value() != newFilterInputModel.Value()
+	m.FilterInput = newFilterInputModel
+
+	if filterChanged {
+		m.updateKeybindings()
+		if m.filterDebounce > 0 {
+			return tea.Batch(cmd, m.debounceFilter())
+		}
+		m.applyFilter()
+	}
+	return cmd
+}
+
+// startFiltering focuses the filter input so the user can type a query.
+func (m *Model) startFiltering() tea.Cmd {
+	m.filterState = Filtering
+	m.FilterInput.CursorEnd()
+	cmd := m.FilterInput.Focus()
+	m.updateKeybindings()
+	return cmd
+}
+
+// acceptFilter stops editing the filter and keeps the current query applied.
+func (m *Model) acceptFilter() {
+	m.FilterInput.Blur()
+	m.filterState = FilterApplied
+	m.updateKeybindings()
+
+	if m.FilterInput.Value() == "" {
+		m.resetFiltering()
+	}
+}
+
+func (m *Model) resetFiltering() {
+	if m.filterState == Unfiltered {
+		return
+	}
+
+	selected := m.SelectedIndex()
+	m.filterState = Unfiltered
+	m.FilterInput.Reset()
+	m.FilterInput.Blur()
+	m.updateVisibleRows()
+	m.restoreCursor(selected)
+	m.updateKeybindings()
+}
+
+// applyFilter recomputes the visible rows from the filter value, keeping the
+// cursor on the selected row when it still matches, or moving it according to
+// the filter cursor policy otherwise.
+func (m *Model) applyFilter() {
+	selected := m.SelectedIndex()
+	m.updateVisibleRows()
+	if m.filterCursorPolicy == FilterCursorNearest {
+		selected = m.nearestVisibleRow(selected)
+	}
+	m.restoreCursor(selected)
+}
+
+// nearestVisibleRow returns the index in rows of the visible row closest to
+// the row at the given index in the order the rows are shown, preferring the
+// following one, or -1 if no row is visible.
+func (m Model) nearestVisibleRow(index int) int {
+	if index < 0 || index >= len(m.rows) {
+		return -1
+	}
+	order := m.sorted
+	if order == nil {
+		order = columnRange(0, len(m.rows))
+	}
+	visible := make(map[int]bool, m.numRows())
+	for i := 0; i < m.numRows(); i++ {
+		visible[m.rowIndex(i)] = true
+	}
+	pos := slices.Index(order, index)
+	for d := 0; d < len(order); d++ {
+		if next := pos + d; next < len(order) && visible[order[next]] {
+			return order[next]
+		}
+		if prev := pos - d; prev >= 0 && visible[order[prev]] {
+			return order[prev]
+		}
+	}
+	return -1
+}
+
+// restoreCursor moves the cursor to the visible position of the given
+// underlying row, or to the first row if it is not visible.
+func (m *Model) restoreCursor(underlying int) {
+	m.cursor = 0
+	if i, ok := m.VisibleIndex(underlying); ok {
+		m.cursor = i
+	}
+	m.onResize()
+}
+
+// matchesFilter reports whether the row matches the filter query, with the
+// filter matcher if set. Otherwise, it reports whether any of the filtered
+// columns contains the query, ignoring case.
+func (m Model) matchesFilter(row Row, query string) bool {
+	if m.filterMatcher != nil {
+		return m.filterMatcher(row, query)
+	}
+	query = strings.ToLower(query)
+	contains := func(col int) bool {
+		return strings.Contains(strings.ToLower(cellAt(row, col)), query)
+	}
+	if m.filterColumns != nil {
+		for _, col := range m.filterColumns {
+			if col >= 0 && contains(col) {
+				return true
+			}
+		}
+		return false
+	}
+	for col := 0; col < m.numColumns(); col++ {
+		if contains(col) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package table
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	lipglosstable "github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// WithCellClick sets a function called when a data cell is clicked with the
+// left mouse button, with the visible index of the row and the index of the
+// column. The cursor moves to the clicked cell first. The command it returns
+// is returned by Update. Mouse events must be enabled in the program, and the
+// position of the table set with WithOrigin when it is not rendered at the top
+// left corner of the terminal.
+func WithCellClick(fn func(row, col int) tea.Cmd) Option {
+	return func(m *Model) {
+		m.cellClick = fn
+	}
+}
+
+// WithHeaderClick sets a function called when a header cell is clicked with
+// the left mouse button, with the index of the column. The command it returns
+// is returned by Update.
+func WithHeaderClick(fn func(col int) tea.Cmd) Option {
+	return func(m *Model) {
+		m.headerClick = fn
+	}
+}
+
+// WithOrigin sets the position in the terminal of the top left corner of the
+// view of the table, which mouse events are relative to. It is (0, 0) by
+// default.
+func WithOrigin(x, y int) Option {
+	return func(m *Model) {
+		m.originX, m.originY = x, y
+	}
+}
+
+// SetOrigin sets the position in the terminal of the top left corner of the
+// view of the table.
+func (m *Model) SetOrigin(x, y int) {
+	WithOrigin(x, y)(m)
+}
+
+// handleMouse handles clicks on the cells of the table.
+func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return nil
+	}
+	row, col, ok := m.hitTest(msg.X, msg.Y)
+	switch {
+	case !ok:
+		return nil
+	case row == lipglosstable.HeaderRow:
+		var cmd tea.Cmd
+		if m.headerClick != nil {
+			cmd = m.headerClick(col)
+		}
+		if m.clickToSort {
+			m.toggleSort(col)
+		}
+		return cmd
+	default:
+		m.SetCursor(row)
+		m.SetColCursor(col)
+		if m.cellClick != nil {
+			return m.cellClick(row, col)
+		}
+		return nil
+	}
+}
+
+// hitTest returns the visible row, or lipglosstable.HeaderRow, and the column
+// of the data cell at the given terminal position.
+func (m Model) hitTest(x, y int) (row, col int, ok bool) {
+	x -= m.originX + m.margin.GetMarginLeft()
+	y -= m.originY + m.margin.GetMarginTop()
+	if title := m.titleView(); title != "" {
+		y -= lipgloss.Height(title)
+	}
+	if m.filterState != Unfiltered {
+		y -= lipgloss.Height(m.FilterInput.View())
+	}
+	if m.rowTemplate != nil {
+		return m.templateHitTest(x, y)
+	}
+
+	maxColumnWidths := m.getMaxColumnWidths()
+	columns := m.visibleColumns(maxColumnWidths)
+	col = -1
+	for i, bounds := range m.columnBounds() {
+		if i < len(columns) && x >= bounds[0] && x < bounds[1] {
+			col = columns[i]
+		}
+	}
+	if col < 0 {
+		return 0, 0, false
+	}
+
+	// The header is right below the top border.
+	if len(m.cols) > 0 && y >= 1 && y < m.headerHeight()-1 {
+		return lipglosstable.HeaderRow, col, true
+	}
+	y -= m.headerHeight()
+	if y < 0 || (m.manualHeight != 0 && y >= m.manualHeight) {
+		return 0, 0, false
+	}
+	for _, p := range m.renderPlan() {
+		height := 1
+		if p.kind == dataRow {
+			height = m.rowHeight(p.row)
+		}
+		if y < height {
+			return p.row, col, p.kind == dataRow
+		}
+		y -= height
+	}
+	return 0, 0, false
+}
+
+// templateHitTest returns the visible row of the line rendered with the row
+// template at the given position from the top left corner of the rows, as a
+// hit on its first column.
+func (m Model) templateHitTest(x, y int) (row, col int, ok bool) {
+	plan := m.renderPlan()
+	if y < 0 || y >= len(plan) || x < 0 || x >= lipgloss.Width(m.templateView()) {
+		return 0, 0, false
+	}
+	return plan[y].row, 0, plan[y].kind == dataRow
+}
+
+// columnBounds returns the horizontal range of each rendered column, from the
+// left edge of the table. The table is rendered with a top border marking the
+// columns so that the ranges follow the widths lipgloss settles on.
+func (m Model) columnBounds() [][2]int {
+	const mark = "x"
+	blank := func(s string) string {
+		return strings.Repeat(" ", lipgloss.Width(s))
+	}
+	border := m.border
+	border.Top = mark
+	border.TopLeft = blank(border.TopLeft)
+	border.MiddleTop = blank(border.MiddleTop)
+	border.TopRight = blank(border.TopRight)
+
+	top, _, _ := strings.Cut(ansi.Strip(m.renderTable(border)), "\n")
+	var bounds [][2]int
+	for i, start := 0, -1; i <= len(top); i++ {
+		switch {
+		case i < len(top) && top[i] == mark[0]:
+			if start < 0 {
+				start = i
+			}
+		case start >= 0:
+			bounds = append(bounds, [2]int{start, i})
+			start = -1
+		}
+	}
+	return bounds
+}
@@ -0,0 +1,84 @@
+package table
+
+import (
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// smoothScrollFrames is the number of frames a smooth scroll takes.
+	smoothScrollFrames = 4
+	// smoothScrollInterval is the time between the frames of a smooth scroll.
+	smoothScrollInterval = time.Second / 30
+)
+
+// Internal ID management. Used to tell the frames of a table apart from the
+// ones of other tables.
+var lastID int64
+
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// scrollFrameMsg steps a smooth scroll to its next frame.
+type scrollFrameMsg struct {
+	id  int
+	tag int
+}
+
+// WithSmoothScroll sets whether jumps of more than one row, such as PageDown,
+// scroll the rows into view over a few frames instead of at once. The cursor
+// moves at once. Update returns the commands rendering the frames; navigating
+// again while scrolling scrolls from the current frame to the new target.
+func WithSmoothScroll(smooth bool) Option {
+	return func(m *Model) {
+		m.smoothScroll = smooth
+	}
+}
+
+// animateScroll starts scrolling smoothly from the given first visible row to
+// the current one, cancelling the scroll in progress. It returns the command
+// rendering the next frame, or nil if there is nothing to animate. The scroll
+// in progress goes on if the first visible row did not change.
+func (m *Model) animateScroll(from int) tea.Cmd {
+	if !m.smoothScroll || m.start == from {
+		return nil
+	}
+	m.scrollTag++
+	target := m.start
+	distance := max(target-from, from-target)
+	if distance <= 1 {
+		return nil
+	}
+	m.scrollTarget = target
+	m.scrollStep = (distance + smoothScrollFrames - 1) / smoothScrollFrames
+	m.start = from
+	return m.scrollFrameTick()
+}
+
+// scrollFrame moves the first visible row one step towards the target of the
+// smooth scroll, and returns the command rendering the next frame, if any.
+func (m *Model) scrollFrame(msg scrollFrameMsg) tea.Cmd {
+	if msg.id != m.id || msg.tag != m.scrollTag {
+		return nil
+	}
+	if m.start < m.scrollTarget {
+		m.start = min(m.start+m.scrollStep, m.scrollTarget)
+	} else {
+		m.start = max(m.start-m.scrollStep, m.scrollTarget)
+	}
+	if m.start == m.scrollTarget {
+		m.onResize()
+		return nil
+	}
+	return m.scrollFrameTick()
+}
+
+func (m Model) scrollFrameTick() tea.Cmd {
+	id, tag := m.id, m.scrollTag
+	return tea.Tick(smoothScrollInterval, func(time.Time) tea.Msg {
+		return scrollFrameMsg{id: id, tag: tag}
+	})
+}
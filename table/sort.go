@@ -0,0 +1,210 @@
+package table
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the layouts tried, in order, to parse the values of date
+// columns.
+var dateLayouts = []string{time.RFC3339, time.DateTime, time.DateOnly}
+
+// SortBy sorts the rows by the values of column col, in descending order if
+// desc is set, comparing them according to the type of the column. The
+// sort is stable and only changes the order in which rows are shown: Rows
+// keeps returning them in the order they were set. The rows stay sorted when
+// they are replaced with SetRows. The cursor stays on the selected row. With
+// WithTree, each row is sorted among its siblings and stays followed by its
+// children, themselves sorted.
+//
+// When the rows are also filtered, the visible rows are the rows matching the
+// filter in sorted order. Since the sort is stable and each row matches the
+// filter on its own, filtering the rows before or after sorting them gives the
+// same rows in the same order.
+func (m *Model) SortBy(col int, desc bool) {
+	if col < 0 || col >= m.numColumns() {
+		return
+	}
+	selected := m.SelectedIndex()
+	m.sortColumn, m.sortDesc = col, desc
+	m.sortRows()
+	m.updateVisibleRows()
+	m.restoreCursor(selected)
+	m.updateKeybindings()
+}
+
+// SortState returns the column the rows are sorted by and whether they are
+// sorted in descending order. ok is false when the rows are not sorted.
+func (m Model) SortState() (col int, desc bool, ok bool) {
+	if m.sorted == nil {
+		return 0, false, false
+	}
+	return m.sortColumn, m.sortDesc, true
+}
+
+// WithClickToSort sets whether clicking a header, or pressing the ToggleSort
+// key binding with the column focused, sorts the rows by the column: in
+// ascending order first, then reversing the order on each click. Clicks are
+// handled after the function set with WithHeaderClick, if any.
+func WithClickToSort(enabled bool) Option {
+	return func(m *Model) {
+		m.clickToSort = enabled
+		m.updateKeybindings()
+	}
+}
+
+// toggleSort sorts by column col in ascending order, or reverses the order if
+// the rows are already sorted by it.
+func (m *Model) toggleSort(col int) {
+	if m.sorted != nil && m.sortColumn == col {
+		m.SortBy(col, !m.sortDesc)
+		return
+	}
+	m.SortBy(col, false)
+}
+
+// sortIndicator returns the indicator appended to the title of the sorted
+// column.
+func sortIndicator(desc bool) string {
+	if desc {
+		return " ▼"
+	}
+	return " ▲"
+}
+
+// ClearSort shows the rows in the order they were set again. The cursor stays
+// on the selected row.
+func (m *Model) ClearSort() {
+	if m.sorted == nil {
+		return
+	}
+	selected := m.SelectedIndex()
+	m.sorted = nil
+	m.updateVisibleRows()
+	m.restoreCursor(selected)
+	m.updateKeybindings()
+}
+
+// sortRows computes the order of the rows from the sort column.
+func (m *Model) sortRows() {
+	m.sorted = m.sortSubtrees(0, len(m.rows))
+}
+
+// sortSubtrees returns the indexes of the rows from lo to hi, sorting the
+// rows of the same level among themselves, each followed by its sorted
+// children. Without a tree, all the rows are at the same level.
+func (m Model) sortSubtrees(lo, hi int) []int {
+	// Bounds of the subtrees: [starts[k], starts[k+1]).
+	var starts []int
+	for i := lo; i < hi; i++ {
+		if i == lo || m.treeDepth(i) <= m.treeDepth(starts[len(starts)-1]) {
+			starts = append(starts, i)
+		}
+	}
+	starts = append(starts, hi)
+
+	subtrees := make([]int, len(starts)-1)
+	for k := range subtrees {
+		subtrees[k] = k
+	}
+	typ := m.columnType(m.sortColumn)
+	slices.SortStableFunc(subtrees, func(a, b int) int {
+		ra, rb := m.rows[starts[a]], m.rows[starts[b]]
+		c := compareValues(typ, cellAt(ra, m.sortColumn), cellAt(rb, m.sortColumn))
+		if m.sortDesc {
+			return -c
+		}
+		return c
+	})
+
+	sorted := make([]int, 0, hi-lo)
+	for _, k := range subtrees {
+		root, end := starts[k], starts[k+1]
+		sorted = append(sorted, root)
+		if end > root+1 {
+			sorted = append(sorted, m.sortSubtrees(root+1, end)...)
+		}
+	}
+	return sorted
+}
+
+// columnType returns the type of column col.
+func (m Model) columnType(col int) ColumnType {
+	if col >= 0 && col < len(m.cols) {
+		return m.cols[col].Type
+	}
+	return StringColumn
+}
+
+// cellAt returns the value of column col of row, or an empty string if the row
+// is shorter.
+func cellAt(row Row, col int) string {
+	if col >= 0 && col < len(row) {
+		return row[col]
+	}
+	return ""
+}
+
+// compareValues compares two cell values of a column of the given type. Values
+// that can not be parsed as the type of the column sort after the ones that
+// can, and are compared as text.
+func compareValues(typ ColumnType, a, b string) int {
+	switch typ {
+	case NumberColumn:
+		return compareParsed(a, b, parseNumber, cmp.Compare[float64])
+	case DateColumn:
+		return compareParsed(a, b, parseDate, time.Time.Compare)
+	case BoolColumn:
+		return compareParsed(a, b, parseBool, compareBool)
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareParsed[T any](a, b string, parse func(string) (T, bool), compare func(T, T) int) int {
+	va, aok := parse(a)
+	vb, bok := parse(b)
+	switch {
+	case aok && bok:
+		return compare(va, vb)
+	case aok:
+		return -1
+	case bok:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return f, err == nil
+}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseBool(s string) (bool, bool) {
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	return b, err == nil
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,83 @@
+package table
+
+import "sort"
+
+// TableStateVersion is the version of the TableState returned by State.
+const TableStateVersion = 1
+
+// TableState holds the view state of a table, as returned by State, so that it
+// can be saved and restored with SetState. It does not hold the rows.
+type TableState struct {
+	// Version of the state. Fields added in later versions keep their zero
+	// value when restoring an older state.
+	Version int
+
+	// Cursor is the visible index of the selected row.
+	Cursor int
+	// Start is the visible index of the first row scrolled into view.
+	Start int
+	// ColCursor is the index of the focused column.
+	ColCursor int
+	// ColStart is the index of the first column scrolled into view.
+	ColStart int
+
+	// Filter is the query of the applied filter, empty when unfiltered.
+	Filter string
+
+	// Sorted reports whether the rows are sorted by SortColumn, in descending
+	// order if SortDesc is set.
+	Sorted     bool
+	SortColumn int
+	SortDesc   bool
+
+	// Expanded holds the indexes in rows of the expanded rows.
+	Expanded []int
+}
+
+// State returns the view state of the table: cursor, scroll position, filter,
+// sort and expanded rows.
+func (m Model) State() TableState {
+	s := TableState{
+		Version:    TableStateVersion,
+		Cursor:     m.cursor,
+		Start:      m.start,
+		ColCursor:  m.colCursor,
+		ColStart:   m.colStart,
+		Sorted:     m.sorted != nil,
+		SortColumn: m.sortColumn,
+		SortDesc:   m.sortDesc,
+		Filter:     m.FilterQuery(),
+	}
+	for i, expanded := range m.expanded {
+		if expanded {
+			s.Expanded = append(s.Expanded, i)
+		}
+	}
+	sort.Ints(s.Expanded)
+	return s
+}
+
+// SetState restores a view state returned by State. A filter is restored as
+// applied, even if the user was editing it.
+func (m *Model) SetState(s TableState) {
+	m.sorted = nil
+	if s.Sorted {
+		m.sortColumn, m.sortDesc = s.SortColumn, s.SortDesc
+		m.sortRows()
+	}
+
+	m.resetFiltering()
+	m.setFilterQuery(s.Filter)
+
+	m.expanded = make(map[int]bool, len(s.Expanded))
+	for _, i := range s.Expanded {
+		m.expanded[i] = true
+	}
+	m.updateVisibleRows()
+
+	m.start = s.Start
+	m.cursor = clamp(s.Cursor, 0, max(0, m.numRows()-1))
+	m.onResize()
+	m.colStart = s.ColStart
+	m.SetColCursor(s.ColCursor)
+}
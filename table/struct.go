@@ -0,0 +1,115 @@
+package table
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ColumnsFromStruct returns the columns of the exported fields of the struct
+// v, or of the struct v points to, in the order they are declared. The title,
+// width and alignment of a column are read from the table tag of its field,
+// like:
+//
+//	Price float64 `table:"Price,width=10,align=right"`
+//
+// The title defaults to the name of the field, and fields tagged "-" have no
+// column. Use RowFromStruct to get the matching rows.
+func ColumnsFromStruct(v any) ([]Column, error) {
+	t, ok := structType(v)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a struct", v)
+	}
+	var cols []Column
+	for _, field := range structFields(t) {
+		col, err := columnFromTag(field)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// RowFromStruct returns the values of the fields of the struct v, or of the
+// struct v points to, that have a column in ColumnsFromStruct, formatted with
+// fmt.Sprint. It returns nil if v is not a struct.
+func RowFromStruct(v any) Row {
+	t, ok := structType(v)
+	if !ok {
+		return nil
+	}
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if !value.IsValid() {
+		return nil
+	}
+	fields := structFields(t)
+	row := make(Row, len(fields))
+	for i, field := range fields {
+		row[i] = fmt.Sprint(value.FieldByIndex(field.Index).Interface())
+	}
+	return row
+}
+
+// structType returns the type of the struct v is or points to.
+func structType(v any) (reflect.Type, bool) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t, t != nil && t.Kind() == reflect.Struct
+}
+
+// structFields returns the exported fields of the struct type t that are not
+// tagged "-".
+func structFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("table") == "-" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// columnFromTag returns the column described by the table tag of the field.
+func columnFromTag(field reflect.StructField) (Column, error) {
+	title, options, _ := strings.Cut(field.Tag.Get("table"), ",")
+	col := Column{Title: title}
+	if col.Title == "" {
+		col.Title = field.Name
+	}
+	if options == "" {
+		return col, nil
+	}
+	for _, option := range strings.Split(options, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch name {
+		case "width":
+			width, err := strconv.Atoi(value)
+			if err != nil || width < 0 {
+				return Column{}, fmt.Errorf("field %s has an invalid width: %q", field.Name, value)
+			}
+			col.Width = width
+		case "align":
+			switch value {
+			case "left":
+				col.Alignment = lipgloss.Left
+			case "center":
+				col.Alignment = lipgloss.Center
+			case "right":
+				col.Alignment = lipgloss.Right
+			default:
+				return Column{}, fmt.Errorf("field %s has an invalid alignment: %q", field.Name, value)
+			}
+		default:
+			return Column{}, fmt.Errorf("field %s has an unknown option: %q", field.Name, option)
+		}
+	}
+	return col, nil
+}
@@ -1,10 +1,18 @@
 package table
 
 import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	lipglosstable "github.com/charmbracelet/lipgloss/table"
@@ -16,21 +24,240 @@ type Model struct {
 	KeyMap KeyMap
 	Help   help.Model
 
+	// FilterInput is the text input used to type the filter query.
+	FilterInput textinput.Model
+
 	cols      []Column
 	rows      []Row
 	cursor    int
+	colCursor int
 	focus     bool
+	styles    Styles
 	styleFunc StyleFunc
+	border    lipgloss.Border
+	// Whether only the focused cell of the selected row is styled as
+	// selected, and whether its other cells are faint.
+	cellSelection  bool
+	dimSelectedRow bool
+	// Whether the highlight of the selected row spans the width of the table.
+	fullWidthSelection bool
+	// Whether the selected row is underlined instead of styled as selected.
+	cursorUnderline bool
 
 	// 0 to be fit height (all rows)
 	manualHeight int
 	// 0 to be fit width (all data)
 	manualWidth int
+	// Whether the rows are loading, and the number of placeholder rows
+	// rendered meanwhile.
+	loading      bool
+	skeletonRows int
+	// Number of cells a value may overflow its column by before it is
+	// truncated.
+	truncateTolerance int
+	// Whether the viewport centers the cursor row when it scrolls.
+	centerOnScroll bool
+	// Whether the columns of the lowest priority are hidden when they do not
+	// fit the width.
+	responsiveHide bool
+	// Whether values spill over into the following empty cells.
+	overflow bool
+	// Whether the number of rows scrolled out of view is rendered below the
+	// table.
+	scrollIndicator bool
+	// Called at the start of View on the copy of the model being rendered.
+	beforeRender func(m *Model)
+	// Title rendered above the table, and whether the row count follows it.
+	title      string
+	titleCount bool
+	// Whether the titles are rendered one character per line.
+	verticalHeader bool
+	// Rows moved by a page. 0 to move by the height.
+	pageSize int
+	// Rows moved by a line. 0 to move by one row.
+	step int
 	// index of rows that is first visible. Changes when scrolling.
 	start int
+	// Number of rows at the top that stay visible when scrolling.
+	frozenRows int
+	// Labels of the separators rendered above rows, by index in rows.
+	separators map[int]string
+	// Maximum number of rows rendered at once. 0 for no limit.
+	renderLimit int
+
+	// index of the first visible column after the row label. Changes when
+	// scrolling horizontally.
+	colStart int
+	// Whether columns that do not fit manualWidth are scrolled into view.
+	horizontalScroll bool
+	// Whether the first column is a row label that stays visible when
+	// scrolling horizontally.
+	rowLabel bool
+	// Marker of the selected row in a gutter before the columns. Empty for
+	// no gutter.
+	indicator string
+	// Returns the icon rendered in a gutter before the columns for a row.
+	// nil for no gutter.
+	rowIcon func(row int) string
+	// Whether a gutter with row numbers is rendered before the columns.
+	lineNumbers bool
+	// Whether row numbers count from the first visible row rather than from
+	// the first row.
+	relativeLineNumbers bool
 
 	// Whether to wrap cursor on LineUp/LineDown
 	wrapCursor bool
+	// Receives the cursor whenever it changes. nil to not mirror it.
+	cursorBinding *int
+
+	// Identifies the frames of the smooth scrolls of this table.
+	id int
+	// Whether jumps of more than a row scroll over a few frames.
+	smoothScroll bool
+	// Tag of the frames of the smooth scroll in progress; frames with an older
+	// tag are ignored.
+	scrollTag int
+	// First visible row the smooth scroll in progress ends at, and the number
+	// of rows it scrolls per frame.
+	scrollTarget int
+	scrollStep   int
+
+	// Number of columns between tab stops when expanding tabs in cell values.
+	// 0 to leave tabs untouched.
+	tabWidth int
+	// Whether to remove ANSI escape sequences from cell values when rendering.
+	stripAnsi bool
+	// Rendered in place of empty cell values.
+	emptyCell string
+	// Rendered in place of true and false values in bool columns, and in the
+	// other columns too if boolGlyphs is set.
+	trueGlyph  string
+	falseGlyph string
+	boolGlyphs bool
+	// Columns whose negative numbers render in parentheses.
+	accountingColumns map[int]bool
+	// Functions returning the color of the numbers of a column, by column.
+	colorScales map[int]func(float64) lipgloss.Color
+	// Columns rendered as their difference from the baseline row, by index in
+	// rows, or -1 for no baseline.
+	deltaColumns map[int]bool
+	baselineRow  int
+	// Columns where a value equal to the one of the row above renders blank.
+	collapseDuplicates map[int]bool
+	// Derives the rendered values of a row from the whole row. nil to render
+	// the stored values.
+	rowTransform func(Row) Row
+	// Render the cells of a column given its width, such as bars.
+	cellRenderers map[int]cellRenderer
+	// How often the relative times are rendered again. 0 not to.
+	relativeTimeRefresh time.Duration
+
+	// Horizontal padding of the header and cells, overriding the styles. nil
+	// to keep the padding of the styles.
+	padding *padding
+	// Horizontal padding of a column, overriding padding.
+	columnPaddings map[int]padding
+
+	// Called when a data cell or a header cell is clicked. nil to ignore
+	// clicks.
+	cellClick   func(row, col int) tea.Cmd
+	headerClick func(col int) tea.Cmd
+	// Position in the terminal of the top left corner of the view, which
+	// mouse events are relative to.
+	originX, originY int
+
+	// Renders the status line below the table. nil to render no caption.
+	caption func(m Model) string
+	// Whether View renders the help below the table.
+	inlineHelp bool
+	// Whether blank rows fill the height left when the rows are shorter.
+	fillHeight bool
+	// Whether the cells of alternating columns render with Styles.ColEven and
+	// Styles.ColOdd.
+	columnStripes bool
+	// Spacing around the rendered table.
+	margin lipgloss.Style
+	// Renders the content of a header cell. nil to render the column title.
+	headerRenderer func(col int, title string, width int) string
+
+	// Per column width bounds, overriding Column.MinWidth and Column.MaxWidth.
+	// -1 to keep the column's own bound.
+	minWidths []int
+	maxWidths []int
+
+	// Whether rows show only the first line of their cells until expanded.
+	expandable bool
+	// indexes of the expanded rows.
+	expanded map[int]bool
+	// Whether expanding a row collapses the other rows of its group.
+	accordion bool
+	// Indents the first column and hides the descendants of collapsed rows.
+	// nil to show the rows flat.
+	tree *TreeConfig
+	// Identifies a row across calls to SetRows. nil to identify rows by index.
+	rowKey func(Row) string
+	// Whether the cells changed by the last call to SetRows are highlighted.
+	diffHighlight bool
+	// Columns of the cells changed by the last call to SetRows, by index in
+	// rows.
+	changed map[int]map[int]bool
+	// Returns the group of the row at the given index, for accordion rows.
+	groupFunc func(row int) string
+	// Whether a divider is rendered between the rows whose values differ in
+	// column groupDividerCol.
+	groupDividers   bool
+	groupDividerCol int
+
+	// Column and predicate of the rows the NextMatch and PrevMatch key
+	// bindings jump to.
+	jumpColumn int
+	jumpFunc   func(string) bool
+
+	// Actions disabled with SetActionEnabled.
+	disabledActions map[Action]bool
+	// Whether the visible rows overflow the viewport, enabling the paging
+	// actions.
+	paging bool
+
+	// Template the rows are rendered with instead of columns, given the
+	// values of the columns by name.
+	rowTemplate    *template.Template
+	templateFields []string
+	// How the columns are widened to the width of the table.
+	widthFill FillMode
+	// Whether Update sends messages when the selection or the viewport
+	// change.
+	changeMessages bool
+	// Whether the cursor follows the appended rows from the last row.
+	follow bool
+	// Whether FromValues and FromCSV take the column titles from the first
+	// line.
+	firstRowHeader bool
+	// Whether clicking a header, or the ToggleSort key binding, sorts by its
+	// column.
+	clickToSort bool
+	// Column the rows are sorted by, in descending order if sortDesc is set.
+	sortColumn int
+	sortDesc   bool
+	// indexes of the rows in sorted order. nil when the rows are not sorted.
+	sorted []int
+
+	filteringEnabled bool
+	filterState      FilterState
+	// How long the rows wait to be filtered after the query changes, and the
+	// tag of the last scheduled filtering.
+	filterDebounce time.Duration
+	filterTag      int
+	// Reports whether a row matches the filter query. nil to search the
+	// filter columns.
+	filterMatcher func(row Row, query string) bool
+	// Where the cursor moves when the selected row stops matching.
+	filterCursorPolicy FilterCursorPolicy
+	// Columns searched for the filter query. nil to search all columns.
+	filterColumns []int
+	// indexes of the visible rows, in the order they are shown. nil when all
+	// rows are shown in the order they were set.
+	visible []int
 }
 
 // Row represents one line in the table.
@@ -39,9 +266,48 @@ type Row []string
 // Column defines the table structure.
 type Column struct {
 	Title string
+	// Width of the column. 0 to fit the content.
 	Width int
+	// MinWidth and MaxWidth bound the width of a column fitting its content.
+	// 0 for no bound.
+	MinWidth int
+	MaxWidth int
+	// Type of the values of the column, which sets how they are aligned,
+	// sorted and rendered by default.
+	Type ColumnType
+	// Alignment of the title and the cells. The zero value, lipgloss.Left, is
+	// replaced by lipgloss.Right for number and date columns.
+	Alignment lipgloss.Position
+	// HeaderAlignment of the title, if different from the one of the cells.
+	// The zero value, lipgloss.Left, aligns the title like the cells.
+	HeaderAlignment lipgloss.Position
+	// Wrap sets whether the values wider than the column wrap onto more lines
+	// instead of being truncated. The row is as tall as its tallest cell.
+	Wrap bool
+	// Abbrev is the title shown in the header when Title does not fit the
+	// width of the column. It is truncated if it does not fit either.
+	Abbrev string
+	// Priority of the column to stay rendered with WithResponsiveHide: the
+	// columns of the lowest priority are hidden first.
+	Priority int
+}
+
+// padding is the horizontal padding of a column.
+type padding struct {
+	left, right int
 }
 
+// ColumnType describes the kind of values in a column.
+type ColumnType int
+
+// Possible column types.
+const (
+	StringColumn ColumnType = iota // left aligned, sorted as text
+	NumberColumn                   // right aligned, sorted numerically
+	DateColumn                     // right aligned, sorted chronologically
+	BoolColumn                     // rendered as ✓ or ✗, false sorts first
+)
+
 // KeyMap defines keybindings. It satisfies to the help.KeyMap interface, which
 // is used to render the help menu.
 type KeyMap struct {
@@ -53,6 +319,21 @@ type KeyMap struct {
 	HalfPageDown key.Binding
 	GotoTop      key.Binding
 	GotoBottom   key.Binding
+	ColumnLeft   key.Binding
+	ColumnRight  key.Binding
+	RowHome      key.Binding
+	RowEnd       key.Binding
+	ToggleExpand key.Binding
+	NextMatch    key.Binding
+	PrevMatch    key.Binding
+	Filter       key.Binding
+	ClearFilter  key.Binding
+	ToggleSort   key.Binding
+	ResetView    key.Binding
+
+	// Keybindings used when setting a filter.
+	CancelWhileFiltering key.Binding
+	AcceptWhileFiltering key.Binding
 }
 
 // ShortHelp implements the KeyMap interface.
@@ -65,6 +346,8 @@ func (km KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{km.LineUp, km.LineDown, km.GotoTop, km.GotoBottom},
 		{km.PageUp, km.PageDown, km.HalfPageUp, km.HalfPageDown},
+		{km.ColumnLeft, km.ColumnRight, km.RowHome, km.RowEnd, km.ToggleExpand, km.NextMatch, km.PrevMatch, km.ToggleSort},
+		{km.Filter, km.ClearFilter, km.AcceptWhileFiltering, km.CancelWhileFiltering, km.ResetView},
 	}
 }
 
@@ -104,15 +387,122 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("end", "G"),
 			key.WithHelp("G/end", "go to end"),
 		),
+		ColumnLeft: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "left"),
+		),
+		ColumnRight: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "right"),
+		),
+		RowHome: key.NewBinding(
+			key.WithKeys("0", "^"),
+			key.WithHelp("0", "first column"),
+		),
+		RowEnd: key.NewBinding(
+			key.WithKeys("$"),
+			key.WithHelp("$", "last column"),
+		),
+		ToggleExpand: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "expand"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "prev match"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear filter"),
+		),
+		ToggleSort: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "sort"),
+		),
+		ResetView: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reset view"),
+		),
+		CancelWhileFiltering: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		AcceptWhileFiltering: key.NewBinding(
+			key.WithKeys("enter", "tab", "shift+tab", "up", "down"),
+			key.WithHelp("enter", "apply filter"),
+		),
+	}
+}
+
+// MergeKeyMap returns base with its bindings replaced by the ones set in
+// override. A binding is considered unset when it has no keys, like the zero
+// key.Binding, so overriding with a disabled binding that has keys disables it.
+//
+//	km := MergeKeyMap(DefaultKeyMap(), KeyMap{
+//		LineDown: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "down")),
+//	})
+func MergeKeyMap(base, override KeyMap) KeyMap {
+	merge := func(b *key.Binding, o key.Binding) {
+		if len(o.Keys()) > 0 {
+			*b = o
+		}
 	}
+	merge(&base.LineUp, override.LineUp)
+	merge(&base.LineDown, override.LineDown)
+	merge(&base.PageUp, override.PageUp)
+	merge(&base.PageDown, override.PageDown)
+	merge(&base.HalfPageUp, override.HalfPageUp)
+	merge(&base.HalfPageDown, override.HalfPageDown)
+	merge(&base.GotoTop, override.GotoTop)
+	merge(&base.GotoBottom, override.GotoBottom)
+	merge(&base.ColumnLeft, override.ColumnLeft)
+	merge(&base.ColumnRight, override.ColumnRight)
+	merge(&base.RowHome, override.RowHome)
+	merge(&base.RowEnd, override.RowEnd)
+	merge(&base.ToggleExpand, override.ToggleExpand)
+	merge(&base.NextMatch, override.NextMatch)
+	merge(&base.PrevMatch, override.PrevMatch)
+	merge(&base.Filter, override.Filter)
+	merge(&base.ClearFilter, override.ClearFilter)
+	merge(&base.ToggleSort, override.ToggleSort)
+	merge(&base.ResetView, override.ResetView)
+	merge(&base.CancelWhileFiltering, override.CancelWhileFiltering)
+	merge(&base.AcceptWhileFiltering, override.AcceptWhileFiltering)
+	return base
 }
 
 // Styles contains style definitions for this list component. By default, these
 // values are generated by DefaultStyles.
 type Styles struct {
-	Header   lipgloss.Style
-	Cell     lipgloss.Style
-	Selected lipgloss.Style
+	Header          lipgloss.Style
+	Cell            lipgloss.Style
+	Selected        lipgloss.Style
+	Caption         lipgloss.Style
+	RowLabel        lipgloss.Style
+	LineNumber      lipgloss.Style
+	Filler          lipgloss.Style
+	Changed         lipgloss.Style
+	ColEven         lipgloss.Style
+	ColOdd          lipgloss.Style
+	Separator       lipgloss.Style
+	Indicator       lipgloss.Style
+	Skeleton        lipgloss.Style
+	CursorLine      lipgloss.Style
+	GroupDivider    lipgloss.Style
+	Negative        lipgloss.Style
+	Icon            lipgloss.Style
+	Title           lipgloss.Style
+	Badge           lipgloss.Style
+	Positive        lipgloss.Style
+	ScrollIndicator lipgloss.Style
 }
 
 type StyleFunc func(m Model, row int, col int) lipgloss.Style
@@ -120,39 +510,73 @@ type StyleFunc func(m Model, row int, col int) lipgloss.Style
 // DefaultStyles returns a set of default style definitions for this table.
 func DefaultStyles() Styles {
 	return Styles{
-		Selected: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")),
-		Header:   lipgloss.NewStyle().Bold(true).Padding(0, 1),
-		Cell:     lipgloss.NewStyle().Padding(0, 1),
+		Selected:        lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")),
+		Header:          lipgloss.NewStyle().Bold(true).Padding(0, 1),
+		Cell:            lipgloss.NewStyle().Padding(0, 1),
+		Caption:         lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		RowLabel:        lipgloss.NewStyle().Bold(true).Padding(0, 1),
+		LineNumber:      lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Padding(0, 1),
+		Filler:          lipgloss.NewStyle().Padding(0, 1),
+		Changed:         lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		ColEven:         lipgloss.NewStyle(),
+		ColOdd:          lipgloss.NewStyle().Background(lipgloss.Color("236")),
+		Separator:       lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Padding(0, 1),
+		Indicator:       lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")).PaddingLeft(1),
+		Skeleton:        lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Padding(0, 1),
+		CursorLine:      lipgloss.NewStyle().Underline(true),
+		GroupDivider:    lipgloss.NewStyle().Foreground(lipgloss.Color("238")).Padding(0, 1),
+		Negative:        lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+		Icon:            lipgloss.NewStyle().Padding(0, 1),
+		Title:           lipgloss.NewStyle().Bold(true),
+		Badge:           lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		Positive:        lipgloss.NewStyle().Foreground(lipgloss.Color("42")),
+		ScrollIndicator: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 	}
 }
 
 // SetStyles sets the table styles.
 func (m *Model) SetStyles(s Styles) {
-	m.SetStyleFunc(stylesToStyleFunc(s))
+	WithStyles(s)(m)
+}
+
+// inheritStyle returns style with the unset values taken from base, including
+// the padding.
+func inheritStyle(style, base lipgloss.Style) lipgloss.Style {
+	style = style.Inherit(base)
+	// Copy values that are ignored by inherit
+	if style.GetPaddingTop() == 0 {
+		style = style.PaddingTop(base.GetPaddingTop())
+	}
+	if style.GetPaddingBottom() == 0 {
+		style = style.PaddingBottom(base.GetPaddingBottom())
+	}
+	if style.GetPaddingLeft() == 0 {
+		style = style.PaddingLeft(base.GetPaddingLeft())
+	}
+	if style.GetPaddingRight() == 0 {
+		style = style.PaddingRight(base.GetPaddingRight())
+	}
+	return style
 }
 
 func stylesToStyleFunc(s Styles) StyleFunc {
 	return func(m Model, row int, col int) lipgloss.Style {
+		cell := s.Cell
+		if m.rowLabel && col == 0 {
+			cell = s.RowLabel
+		}
 		if row == lipglosstable.HeaderRow {
 			return s.Header
-		} else if row == m.Cursor() {
-			selected := s.Selected.Inherit(s.Cell)
-			// Copy values that are ignored by inherit
-			if selected.GetPaddingTop() == 0 {
-				selected = selected.PaddingTop(s.Cell.GetPaddingTop())
-			}
-			if selected.GetPaddingBottom() == 0 {
-				selected = selected.PaddingBottom(s.Cell.GetPaddingBottom())
+		} else if row == m.Cursor() && !m.cursorUnderline {
+			if m.cellSelection && col != m.ColCursor() {
+				if m.dimSelectedRow {
+					return cell.Faint(true)
+				}
+				return cell
 			}
-			if selected.GetPaddingLeft() == 0 {
-				selected = selected.PaddingLeft(s.Cell.GetPaddingLeft())
-			}
-			if selected.GetPaddingRight() == 0 {
-				selected = selected.PaddingRight(s.Cell.GetPaddingRight())
-			}
-			return selected
+			return inheritStyle(s.Selected, cell)
 		} else {
-			return s.Cell
+			return cell
 		}
 	}
 }
@@ -164,23 +588,60 @@ type Option func(*Model)
 
 // New creates a new model for the table widget.
 func New(opts ...Option) Model {
+	filterInput := textinput.New()
+	filterInput.Prompt = "Filter: "
+
 	m := Model{
-		cursor: 0,
-		KeyMap: DefaultKeyMap(),
-		Help:   help.New(),
+		id:          nextID(),
+		cursor:      0,
+		KeyMap:      DefaultKeyMap(),
+		Help:        help.New(),
+		FilterInput: filterInput,
+		border:      lipgloss.RoundedBorder(),
+		trueGlyph:   "✓",
+		falseGlyph:  "✗",
+		baselineRow: -1,
 	}
-	m.styleFunc = stylesToStyleFunc(DefaultStyles())
+	m.styles = DefaultStyles()
+	m.styleFunc = stylesToStyleFunc(m.styles)
 
 	for _, opt := range opts {
 		opt(&m)
 	}
+	m.updateKeybindings()
 	return m
 }
 
+// Configure applies a batch of changes to the model, such as options, then
+// brings the state derived from them up to date once: the order of the sorted
+// rows, the rows matching the filter, the key bindings, and the cursors and
+// the viewport, clamped to the rows and columns. For example:
+//
+//	table.Configure(func(m *Model) {
+//		WithRows(rows)(m)
+//		WithHeight(10)(m)
+//	})
+func (m *Model) Configure(fn func(*Model)) {
+	fn(m)
+	if m.sorted != nil {
+		if m.sortColumn < m.numColumns() {
+			m.sortRows()
+		} else {
+			m.sorted = nil
+		}
+	}
+	m.updateVisibleRows()
+	m.cursor = clamp(m.cursor, 0, max(0, m.numRows()-1))
+	m.onResize()
+	m.SetColCursor(m.colCursor)
+	m.updateKeybindings()
+}
+
 // WithColumns sets the table columns (headers).
 func WithColumns(cols []Column) Option {
 	return func(m *Model) {
 		m.cols = cols
+		m.SetColCursor(m.colCursor)
 	}
 }
 
@@ -188,303 +649,2622 @@ func WithColumns(cols []Column) Option {
 func WithRows(rows []Row) Option {
 	return func(m *Model) {
 		m.rows = rows
+		m.SetColCursor(m.colCursor)
 	}
 }
 
-// WithHeight sets the height of the table.
-func WithHeight(h int) Option {
+// WithFirstRowHeader sets whether FromValues and FromCSV take the titles of
+// the columns from the first line instead of importing it as a row. The other
+// settings of the existing columns, like their widths, are kept.
+func WithFirstRowHeader(enabled bool) Option {
 	return func(m *Model) {
-		m.manualHeight = h
-		m.onResize()
+		m.firstRowHeader = enabled
 	}
 }
 
-// WithWidth sets the width of the table.
-func WithWidth(w int) Option {
+// WithFollow sets whether the cursor follows the rows appended with AppendRow
+// and AppendRows when it is on the last row, like tail -f.
+func WithFollow(follow bool) Option {
 	return func(m *Model) {
-		m.manualWidth = w
+		m.follow = follow
 	}
 }
 
-// WithFocused sets the focus state of the table.
-func WithFocused(f bool) Option {
+// WithMovementStep sets the number of rows the LineUp and LineDown key
+// bindings move the cursor by. The default is 1.
+func WithMovementStep(n int) Option {
 	return func(m *Model) {
-		m.focus = f
+		m.step = n
 	}
 }
 
-// WithStyles sets the table styles.
-func WithStyles(s Styles) Option {
+// WithPageSize sets the number of rows the PageUp and PageDown key bindings
+// move the cursor by, and the half page key bindings by half of it, instead of
+// the height of the viewport. 0 restores the default.
+func WithPageSize(n int) Option {
 	return func(m *Model) {
-		m.styleFunc = stylesToStyleFunc(s)
+		m.pageSize = n
 	}
 }
 
-func WithStyleFunc(styleFunc StyleFunc) Option {
+// WithVerticalHeaders sets whether the titles of the columns are rendered
+// vertically, one character per line, so that narrow columns can have long
+// titles. The header is as tall as the longest title.
+func WithVerticalHeaders(vertical bool) Option {
 	return func(m *Model) {
-		m.styleFunc = styleFunc
+		m.verticalHeader = vertical
 	}
 }
 
-// SetRows sets a new rows state.
-func (m *Model) SetStyleFunc(styleFunc StyleFunc) {
-	WithStyleFunc(styleFunc)(m)
-}
+// skeletonBar is the glyph the placeholder bars of the skeleton rows are made
+// of.
+const skeletonBar = "░"
 
-// WithKeyMap sets the key map.
-func WithKeyMap(km KeyMap) Option {
+// WithSkeleton sets the number of placeholder rows rendered instead of the
+// rows while the table is loading, as set with SetLoading. Each cell of the
+// placeholder rows is a bar as wide as its column, styled with
+// Styles.Skeleton, so that the layout does not change once the rows are
+// loaded.
+func WithSkeleton(rows int) Option {
 	return func(m *Model) {
-		m.KeyMap = km
+		m.skeletonRows = rows
 	}
 }
 
-func (m *Model) SetWrapCursor(wrapCursor bool) {
-	WithWrapCursor(wrapCursor)(m)
+// SetLoading sets whether the rows are loading, rendering the skeleton rows set
+// with WithSkeleton instead of them.
+func (m *Model) SetLoading(loading bool) {
+	m.loading = loading
 }
 
-func WithWrapCursor(wrapCursor bool) Option {
+// Loading returns whether the rows are loading.
+func (m Model) Loading() bool {
+	return m.loading
+}
+
+// WithTruncateTolerance sets by how many cells a value may be wider than its
+// column and still be shown in full: the column is widened to fit it rather
+// than truncating it. 0, the default, truncates all the values that do not
+// fit.
+func WithTruncateTolerance(n int) Option {
 	return func(m *Model) {
-		m.wrapCursor = wrapCursor
+		m.truncateTolerance = n
 	}
 }
 
-// Update is the Bubble Tea update loop.
-func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
-	if !m.focus {
-		return m, nil
+// WithOverflow sets whether values wider than their column spill over into
+// the following cells of the row while they are empty, like in a spreadsheet,
+// rather than being truncated. The spill stops at the first cell with a value.
+// Values spanning multiple lines and values of columns with a renderer never
+// spill.
+func WithOverflow(overflow bool) Option {
+	return func(m *Model) {
+		m.overflow = overflow
 	}
+}
 
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, m.KeyMap.LineUp):
-			if m.cursor == 0 && m.wrapCursor {
-				m.SetCursor(len(m.rows) - 1)
-			} else {
-				m.MoveUp(1)
-			}
-		case key.Matches(msg, m.KeyMap.LineDown):
-			if m.cursor == len(m.rows)-1 && m.wrapCursor {
-				m.SetCursor(0)
-			} else {
-				m.MoveDown(1)
-			}
-		case key.Matches(msg, m.KeyMap.PageUp):
-			m.MoveUp(m.Height())
-		case key.Matches(msg, m.KeyMap.PageDown):
-			m.MoveDown(m.Height())
-		case key.Matches(msg, m.KeyMap.HalfPageUp):
-			m.MoveUp(m.Height() / 2) //nolint:mnd
-		case key.Matches(msg, m.KeyMap.HalfPageDown):
-			m.MoveDown(m.Height() / 2) //nolint:mnd
-		case key.Matches(msg, m.KeyMap.GotoTop):
-			m.GotoTop()
-		case key.Matches(msg, m.KeyMap.GotoBottom):
-			m.GotoBottom()
-		}
+// WithResponsiveHide sets whether, with a width set with WithWidth, columns
+// are hidden when they do not all fit it. The columns of the lowest Priority
+// are hidden first, from the last one, until the others fit. Hidden columns
+// are neither rendered, clicked nor focused: the column navigation steps over
+// them, and the focus moves to the closest rendered column when the focused
+// one gets hidden.
+func WithResponsiveHide(hide bool) Option {
+	return func(m *Model) {
+		m.responsiveHide = hide
+		m.SetColCursor(m.colCursor)
 	}
-
-	return m, nil
 }
 
-// Focused returns the focus state of the table.
-func (m Model) Focused() bool {
-	return m.focus
+// WithScrollIndicator sets whether a line below the table, styled with
+// Styles.ScrollIndicator, shows how many rows are scrolled out of view above
+// and below the viewport, as "↑ 3  ↓ 12". It is rendered only while the rows
+// do not all fit the height, which it is not counted in.
+func WithScrollIndicator(indicator bool) Option {
+	return func(m *Model) {
+		m.scrollIndicator = indicator
+	}
 }
 
-// Focus focuses the table, allowing the user to move around the rows and
-// interact.
-func (m *Model) Focus() {
-	m.focus = true
+// WithHeight sets the height of the table: the number of lines of rows
+// rendered below the header. The header is not counted: it is rendered in
+// full above the rows, however many lines it takes, and is never scrolled.
+func WithHeight(h int) Option {
+	return func(m *Model) {
+		m.manualHeight = h
+		m.onResize()
+	}
 }
 
-// Blur blurs the table, preventing selection or movement.
-func (m *Model) Blur() {
-	m.focus = false
+// WithWidth sets the width of the table.
+func WithWidth(w int) Option {
+	return func(m *Model) {
+		m.manualWidth = w
+		m.SetColCursor(m.colCursor)
+	}
 }
 
-// View renders the component.
-func (m Model) View() string {
-	renderTable := lipglosstable.New()
-
-	renderTable.StyleFunc(func(row, col int) lipgloss.Style {
-		mappedRow := row
-		if row != lipglosstable.HeaderRow {
-			mappedRow = row + m.start
-		}
-		return m.styleFunc(m, mappedRow, col)
-	})
-	maxColumnWidths := m.getMaxColumnWidths()
-	renderTable.Data(tableData{m: m, maxColumnWidths: maxColumnWidths})
-	renderTable.Headers(m.getRenderColumns(maxColumnWidths)...)
-	if m.manualHeight != 0 {
-		// XXX +4 for borders, need to expose computeHeader from lipgloss Table
-		renderTable.Height(m.manualHeight + 4)
-	}
-	if m.manualWidth != 0 {
-		// XXX +2 for borders
-		renderTable.Width(m.manualWidth + 2)
+// WithColumnMinWidths sets the minimum width of the columns fitting their
+// content, by column index. Use -1 to keep the column's own MinWidth. Extra
+// values are ignored and missing ones leave the columns unchanged.
+func WithColumnMinWidths(widths []int) Option {
+	return func(m *Model) {
+		m.minWidths = widths
 	}
-	return renderTable.Render()
 }
 
-func (m Model) getRenderColumns(maxColumnWidths []int) []string {
-	columns := make([]string, len(m.cols))
-	for i, col := range m.cols {
-		data := col.Title
-		data = ansi.Truncate(data, maxColumnWidths[i], "…")
-		padding := strings.Repeat(" ", max(0, maxColumnWidths[i]-lipgloss.Width(data)))
-		columns[i] = data + padding
+// WithColumnMaxWidths sets the maximum width of the columns fitting their
+// content, by column index. Use -1 to keep the column's own MaxWidth. Extra
+// values are ignored and missing ones leave the columns unchanged.
+func WithColumnMaxWidths(widths []int) Option {
+	return func(m *Model) {
+		m.maxWidths = widths
 	}
-	return columns
 }
 
-func (m Model) getMaxColumnWidths() []int {
-	var numColumns int
-	if len(m.cols) > 0 {
-		numColumns = len(m.cols)
-	} else {
-		for _, row := range m.rows {
-			numColumns = max(numColumns, len(row))
-		}
+// WithHorizontalScroll sets whether the columns that do not fit the width of
+// the table are hidden and scrolled into view as the column cursor moves,
+// instead of shrinking all the columns to fit.
+func WithHorizontalScroll(scroll bool) Option {
+	return func(m *Model) {
+		m.horizontalScroll = scroll
 	}
-	maxColumnWidths := make([]int, numColumns)
-	for i, col := range m.cols {
-		if i < len(maxColumnWidths) {
-			maxColumnWidths[i] = max(maxColumnWidths[i], col.Width)
-		} else {
-			break
-		}
+}
+
+// WithRowLabelColumn sets whether the first column is a row label, styled with
+// Styles.RowLabel, that stays visible when scrolling horizontally.
+func WithRowLabelColumn(label bool) Option {
+	return func(m *Model) {
+		m.rowLabel = label
 	}
-	for _, row := range m.rows {
-		for i, col := range row {
-			if i < len(m.cols) && m.cols[i].Width != 0 {
-				break
-			}
-			if i < len(maxColumnWidths) {
-				maxColumnWidths[i] = max(maxColumnWidths[i], lipgloss.Width(col))
-			} else {
-				break
-			}
-		}
-		numColumns = max(numColumns, len(row))
+}
+
+// WithExpandableRows sets whether rows with multi-line cells show only the
+// first line of each cell until they are expanded with the ToggleExpand key
+// binding or Expand.
+func WithExpandableRows(expandable bool) Option {
+	return func(m *Model) {
+		m.expandable = expandable
+		m.updateKeybindings()
 	}
-	return maxColumnWidths
 }
 
-// HelpView is a helper method for rendering the help menu from the keymap.
-// Note that this view is not rendered by default and you must call it
-// manually in your application, where applicable.
-func (m Model) HelpView() string {
-	return m.Help.View(m.KeyMap)
+// WithAccordion sets whether expanding a row collapses the other expanded rows
+// of the same group, as defined by WithGroupFunc. Without a group function all
+// the rows belong to the same group.
+func WithAccordion(accordion bool) Option {
+	return func(m *Model) {
+		m.accordion = accordion
+	}
 }
 
-// SelectedRow returns the selected row.
-// You can cast it to your own implementation.
-func (m Model) SelectedRow() Row {
-	if m.cursor < 0 || m.cursor >= len(m.rows) {
-		return nil
+// WithGroupFunc sets the function returning the group of a row, given its
+// index in Rows, used by accordion rows.
+func WithGroupFunc(groupFunc func(row int) string) Option {
+	return func(m *Model) {
+		m.groupFunc = groupFunc
 	}
+}
 
-	return m.rows[m.cursor]
+// WithGroupDividers renders a divider row, styled with Styles.GroupDivider,
+// between the consecutive visible rows whose values in column col differ, such
+// as the groups of rows sorted by the column. Like separators, dividers are not
+// part of the data: the cursor skips them.
+func WithGroupDividers(col int) Option {
+	return func(m *Model) {
+		m.groupDividers = col >= 0
+		m.groupDividerCol = col
+		m.onResize()
+	}
 }
 
-// Rows returns the current rows.
-func (m Model) Rows() []Row {
-	return m.rows
+// WithLineNumbers sets whether a gutter with row numbers, styled with
+// Styles.LineNumber, is rendered before the columns.
+func WithLineNumbers(lineNumbers bool) Option {
+	return func(m *Model) {
+		m.lineNumbers = lineNumbers
+	}
 }
 
-// Columns returns the current columns.
-func (m Model) Columns() []Column {
-	return m.cols
+// WithSelectionIndicator sets the marker rendered in a gutter before the
+// columns, styled with Styles.Indicator, on the selected row. The gutter is as
+// wide as the marker. An empty marker removes the gutter.
+func WithSelectionIndicator(indicator string) Option {
+	return func(m *Model) {
+		m.indicator = indicator
+	}
 }
 
-// SetRows sets a new rows state.
-func (m *Model) SetRows(r []Row) {
-	m.rows = r
+// WithRowIcon sets the function returning the icon of a row, given its index
+// in Rows, such as a glyph showing its status. Icons are rendered in a gutter
+// before the columns, as wide as the widest icon and styled with Styles.Icon.
+// They are not part of the data. nil removes the gutter.
+func WithRowIcon(icon func(row int) string) Option {
+	return func(m *Model) {
+		m.rowIcon = icon
+	}
 }
 
-// SetColumns sets a new columns state.
-func (m *Model) SetColumns(c []Column) {
-	m.cols = c
+// WithRelativeLineNumbers sets whether the row numbers in the gutter count
+// from the first visible row, and change when scrolling, instead of numbering
+// the rows from the first one.
+func WithRelativeLineNumbers(relative bool) Option {
+	return func(m *Model) {
+		m.relativeLineNumbers = relative
+	}
 }
 
-// SetWidth sets the width of the viewport of the table.
-func (m *Model) SetWidth(w int) {
-	WithWidth(w)(m)
+// WithJumpTarget sets the rows the NextMatch and PrevMatch key bindings jump
+// to: the ones whose value in column col satisfies pred, for example the rows
+// with an "ERROR" status.
+func WithJumpTarget(col int, pred func(string) bool) Option {
+	return func(m *Model) {
+		m.jumpColumn = col
+		m.jumpFunc = pred
+		m.updateKeybindings()
+	}
 }
 
-// SetHeight sets the height of the viewport of the table.
-func (m *Model) SetHeight(h int) {
-	WithHeight(h)(m)
+// WithFocused sets the focus state of the table.
+func WithFocused(f bool) Option {
+	return func(m *Model) {
+		m.focus = f
+	}
 }
 
-// Height returns the viewport height of the table.
-func (m Model) Height() int {
-	if m.manualHeight != 0 {
-		return m.manualHeight
-	} else {
-		return len(m.rows)
+// WithStyles sets the table styles.
+func WithStyles(s Styles) Option {
+	return func(m *Model) {
+		m.styles = s
+		m.styleFunc = stylesToStyleFunc(s)
 	}
 }
 
-// Cursor returns the index of the selected row.
-func (m Model) Cursor() int {
-	return m.cursor
+// WithCellSelection sets whether the Selected style applies only to the
+// focused cell, at the column cursor, instead of to the whole selected row. It
+// only changes the styles set with WithStyles, not a StyleFunc.
+func WithCellSelection(enabled bool) Option {
+	return func(m *Model) {
+		m.cellSelection = enabled
+	}
 }
 
-// SetCursor sets the cursor position in the table.
-func (m *Model) SetCursor(n int) {
-	m.cursor = clamp(n, 0, len(m.rows)-1)
-	m.onResize()
+// WithCursorUnderline sets whether the cells of the selected row are styled
+// with Styles.CursorLine, an underline by default, instead of the Selected
+// style. The underline is layered over the StyleFunc, if set.
+func WithCursorUnderline(underline bool) Option {
+	return func(m *Model) {
+		m.cursorUnderline = underline
+	}
 }
 
-// MoveUp moves the selection up by any number of rows.
-// It can not go above the first row.
-func (m *Model) MoveUp(n int) {
-	m.cursor = clamp(m.cursor-n, 0, len(m.rows)-1)
-	m.onResize()
+// WithFullWidthSelection sets whether, with a width set with WithWidth, the
+// Selected style also applies to the borders between the columns of the
+// selected row, so that its highlight spans the whole width of the table. It
+// has no effect with cell selection or WithCursorUnderline.
+func WithFullWidthSelection(full bool) Option {
+	return func(m *Model) {
+		m.fullWidthSelection = full
+	}
 }
 
-// MoveDown moves the selection down by any number of rows.
-// It can not go below the last row.
-func (m *Model) MoveDown(n int) {
-	m.cursor = clamp(m.cursor+n, 0, len(m.rows)-1)
-	m.onResize()
+// WithDimSelectedRow sets whether, with cell selection, the other cells of the
+// selected row are rendered faint.
+func WithDimSelectedRow(dim bool) Option {
+	return func(m *Model) {
+		m.dimSelectedRow = dim
+	}
 }
 
-// GotoTop moves the selection to the first row.
-func (m *Model) GotoTop() {
-	m.MoveUp(m.cursor)
+func WithStyleFunc(styleFunc StyleFunc) Option {
+	return func(m *Model) {
+		m.styleFunc = styleFunc
+	}
 }
 
-// GotoBottom moves the selection to the last row.
-func (m *Model) GotoBottom() {
-	m.MoveDown(len(m.rows))
+// SetRows sets a new rows state.
+func (m *Model) SetStyleFunc(styleFunc StyleFunc) {
+	WithStyleFunc(styleFunc)(m)
 }
 
-func (m *Model) onResize() {
-	m.start = clamp(m.start, max(m.cursor-(m.Height()-1), 0), m.cursor)
+// WithKeyMap sets the key map.
+func WithKeyMap(km KeyMap) Option {
+	return func(m *Model) {
+		m.KeyMap = km
+		m.updateKeybindings()
+	}
 }
 
-// FromValues create the table rows from a simple string. It uses `\n` by
-// default for getting all the rows and the given separator for the fields on
-// each row.
-func (m *Model) FromValues(value, separator string) {
-	rows := []Row{}
-	for _, line := range strings.Split(value, "\n") {
-		r := Row{}
-		for _, field := range strings.Split(line, separator) {
-			r = append(r, field)
-		}
-		rows = append(rows, r)
+// WithBorder sets the border drawn around the table and between its columns.
+func WithBorder(border lipgloss.Border) Option {
+	return func(m *Model) {
+		m.border = border
 	}
+}
 
-	m.SetRows(rows)
+// Theme bundles the styles, key map, border and density of a table, so many
+// tables can share the same look and bindings.
+type Theme struct {
+	Styles Styles
+	KeyMap KeyMap
+	Border lipgloss.Border
+	// Padding sets the padding of the header and cells of all columns, like
+	// WithColumnPadding. nil keeps the padding of Styles.
+	Padding *ColumnPadding
 }
 
-func clamp(v, low, high int) int {
-	return min(max(v, low), high)
+// ColumnPadding is the number of spaces on the left and right of the header and
+// cells of a column.
+type ColumnPadding struct {
+	Left, Right int
+}
+
+// DefaultTheme returns the theme of a table created with the default options.
+func DefaultTheme() Theme {
+	return Theme{
+		Styles: DefaultStyles(),
+		KeyMap: DefaultKeyMap(),
+		Border: lipgloss.RoundedBorder(),
+	}
+}
+
+// MinimalTheme returns a compact theme without visible borders, showing the
+// selected row in reverse video.
+func MinimalTheme() Theme {
+	return Theme{
+		Styles: Styles{
+			Header:          lipgloss.NewStyle().Bold(true).PaddingRight(1),
+			Cell:            lipgloss.NewStyle().PaddingRight(1),
+			Selected:        lipgloss.NewStyle().Reverse(true),
+			Caption:         lipgloss.NewStyle().Faint(true),
+			RowLabel:        lipgloss.NewStyle().Bold(true).PaddingRight(1),
+			LineNumber:      lipgloss.NewStyle().Faint(true).PaddingRight(1),
+			Filler:          lipgloss.NewStyle().PaddingRight(1),
+			Changed:         lipgloss.NewStyle().Underline(true),
+			ColEven:         lipgloss.NewStyle(),
+			ColOdd:          lipgloss.NewStyle().Faint(true),
+			Separator:       lipgloss.NewStyle().Faint(true).PaddingRight(1),
+			Indicator:       lipgloss.NewStyle().Bold(true),
+			Skeleton:        lipgloss.NewStyle().Faint(true).PaddingRight(1),
+			CursorLine:      lipgloss.NewStyle().Underline(true),
+			GroupDivider:    lipgloss.NewStyle().Faint(true).PaddingRight(1),
+			Negative:        lipgloss.NewStyle(),
+			Icon:            lipgloss.NewStyle().PaddingRight(1),
+			Title:           lipgloss.NewStyle().Bold(true),
+			Badge:           lipgloss.NewStyle().Faint(true),
+			Positive:        lipgloss.NewStyle(),
+			ScrollIndicator: lipgloss.NewStyle().Faint(true),
+		},
+		KeyMap:  DefaultKeyMap(),
+		Border:  lipgloss.HiddenBorder(),
+		Padding: &ColumnPadding{Right: 1},
+	}
+}
+
+// WithTheme sets the styles, key map, border and padding of the table from a
+// theme.
+func WithTheme(theme Theme) Option {
+	return func(m *Model) {
+		WithStyles(theme.Styles)(m)
+		WithKeyMap(theme.KeyMap)(m)
+		WithBorder(theme.Border)(m)
+		if theme.Padding != nil {
+			WithColumnPadding(theme.Padding.Left, theme.Padding.Right)(m)
+		}
+	}
+}
+
+// SetKeyMap sets the key map, keeping the filter bindings in line with the
+// current filter state.
+func (m *Model) SetKeyMap(km KeyMap) {
+	WithKeyMap(km)(m)
+}
+
+// WithCenterOnScroll sets whether the viewport centers the cursor row when it
+// scrolls. The cursor moves freely within the rows in view; once it moves past
+// the first or last of them, the viewport scrolls so that the cursor row is in
+// its middle, as far as the rows allow.
+func WithCenterOnScroll(center bool) Option {
+	return func(m *Model) {
+		m.centerOnScroll = center
+		m.onResize()
+	}
+}
+
+func (m *Model) SetWrapCursor(wrapCursor bool) {
+	WithWrapCursor(wrapCursor)(m)
+}
+
+func WithWrapCursor(wrapCursor bool) Option {
+	return func(m *Model) {
+		m.wrapCursor = wrapCursor
+	}
+}
+
+// WithCursorBinding sets a pointer the cursor is written to whenever it
+// changes, to mirror it into the state of an application without handling
+// messages. Like the rest of the model, it is expected to be used from the
+// Bubble Tea loop only, and is not safe for concurrent use.
+func WithCursorBinding(cursor *int) Option {
+	return func(m *Model) {
+		m.cursorBinding = cursor
+		m.onResize()
+	}
+}
+
+// WithRenderLimit sets the maximum number of rows, including filler rows,
+// rendered at once, which guards against pathological heights. The viewport
+// shows the rows that fit in both the height and the limit, and scrolls to
+// keep the cursor within them. 0, the default, sets no limit.
+func WithRenderLimit(n int) Option {
+	return func(m *Model) {
+		m.renderLimit = max(0, n)
+		m.onResize()
+	}
+}
+
+// WithFrozenRows pins the first n rows below the header, so they stay visible
+// while the rows after them scroll. Frozen rows can still be selected.
+func WithFrozenRows(n int) Option {
+	return func(m *Model) {
+		m.frozenRows = max(0, n)
+		m.onResize()
+	}
+}
+
+// WithTabWidth sets the distance between tab stops used to expand tab
+// characters in cell values when rendering. The stored rows are not modified.
+// A width of 0, the default, leaves tabs as they are.
+func WithTabWidth(w int) Option {
+	return func(m *Model) {
+		m.tabWidth = max(0, w)
+	}
+}
+
+// WithStripAnsi sets whether ANSI escape sequences embedded in cell values are
+// removed before rendering, so that the table styles apply to the whole cell.
+// Width calculation and truncation are ANSI-aware either way.
+func WithStripAnsi(strip bool) Option {
+	return func(m *Model) {
+		m.stripAnsi = strip
+	}
+}
+
+// WithEmptyCell sets a placeholder, such as "—", rendered in place of empty
+// cell values. The stored rows are not modified.
+func WithEmptyCell(placeholder string) Option {
+	return func(m *Model) {
+		m.emptyCell = placeholder
+	}
+}
+
+// WithInlineHelp sets whether View renders the help for the key bindings below
+// the table, as HelpView does. Set Help.ShowAll to render the full help.
+func WithInlineHelp(inline bool) Option {
+	return func(m *Model) {
+		m.inlineHelp = inline
+	}
+}
+
+// WithFillHeight sets whether the table always takes the height of its
+// viewport, rendering blank rows styled with Styles.Filler below the last row
+// when the rows are shorter, so that the surrounding layout does not move.
+// Filler rows can not be selected. It has no effect without a height.
+func WithFillHeight(fill bool) Option {
+	return func(m *Model) {
+		m.fillHeight = fill
+	}
+}
+
+// WithColumnStripes sets whether the cells of alternating columns render with
+// Styles.ColEven and Styles.ColOdd, such as a subtle background for wide
+// tables. Stripes follow the order of the visible columns, starting with an
+// even column, and only fill the values left unset by the cell, selection and
+// StyleFunc styles. The header is not striped.
+func WithColumnStripes(stripes bool) Option {
+	return func(m *Model) {
+		m.columnStripes = stripes
+	}
+}
+
+// WithMargin sets the blank space around the rendered table, using the same
+// shorthand as lipgloss.Style.Margin: one value for all sides, two for
+// vertical and horizontal, three for top, horizontal and bottom, or four for
+// top, right, bottom and left. The margins are part of the output of View.
+func WithMargin(margins ...int) Option {
+	return func(m *Model) {
+		m.margin = lipgloss.NewStyle().Margin(margins...)
+	}
+}
+
+// WithHeaderRenderer sets a function rendering the content of the header cell
+// of each column, for example to add icons or counts to the title. It is given
+// the index of the column, its title and the width available, and the result
+// is truncated or padded to that width.
+func WithHeaderRenderer(renderer func(col int, title string, width int) string) Option {
+	return func(m *Model) {
+		m.headerRenderer = renderer
+	}
+}
+
+// WithColumnPadding sets the padding on the left and right of the header and
+// cells of all columns, replacing the horizontal padding of the styles.
+func WithColumnPadding(left, right int) Option {
+	return func(m *Model) {
+		m.padding = &padding{left: max(0, left), right: max(0, right)}
+		m.SetColCursor(m.colCursor)
+	}
+}
+
+// SetColumnPadding sets the padding on the left and right of the header and
+// cells of column col, overriding WithColumnPadding and the styles.
+func (m *Model) SetColumnPadding(col, left, right int) {
+	if m.columnPaddings == nil {
+		m.columnPaddings = make(map[int]padding)
+	}
+	m.columnPaddings[col] = padding{left: max(0, left), right: max(0, right)}
+	m.SetColCursor(m.colCursor)
+}
+
+// WithColorScale renders the numbers of column col styled with the color the
+// scale returns for each value, such as a gradient from green to red for a
+// heatmap. The color is layered under the styles of the cells: it does not
+// replace the foreground color of the selected row, for example. The values
+// that are not numbers keep the color of their style.
+func WithColorScale(col int, scale func(value float64) lipgloss.Color) Option {
+	return func(m *Model) {
+		if m.colorScales == nil {
+			m.colorScales = map[int]func(float64) lipgloss.Color{}
+		}
+		m.colorScales[col] = scale
+	}
+}
+
+// WithBaselineRow sets the row, by index in the rows, the cells of the columns
+// set with WithDeltaColumns are compared to. -1, the default, renders them as
+// they are.
+func WithBaselineRow(index int) Option {
+	return func(m *Model) {
+		m.baselineRow = index
+	}
+}
+
+// WithDeltaColumns renders the numbers of the given columns as their signed
+// difference from the number of the baseline row set with WithBaselineRow,
+// like "+3" or "-2", styled with Styles.Positive and Styles.Negative. The
+// baseline row and the values that are not numbers render as they are. The
+// stored rows are not modified.
+func WithDeltaColumns(cols ...int) Option {
+	return func(m *Model) {
+		m.deltaColumns = make(map[int]bool, len(cols))
+		for _, col := range cols {
+			m.deltaColumns[col] = true
+		}
+	}
+}
+
+// WithAccountingFormat renders the negative numbers of the given columns in
+// parentheses, like "(42)" for "-42", styled with Styles.Negative. Values that
+// are not numbers render as they are. The stored rows are not modified.
+func WithAccountingFormat(cols ...int) Option {
+	return func(m *Model) {
+		m.accountingColumns = make(map[int]bool, len(cols))
+		for _, col := range cols {
+			m.accountingColumns[col] = true
+		}
+	}
+}
+
+// WithRowTransform sets a function deriving the rendered values of a row from
+// the whole row, such as combining two columns. It is applied when rendering
+// only: the stored rows and SelectedRow are unchanged. The transformed row must
+// have as many cells as the row, otherwise the row renders untransformed. The
+// row must not be modified in place.
+func WithRowTransform(transform func(Row) Row) Option {
+	return func(m *Model) {
+		m.rowTransform = transform
+	}
+}
+
+// WithRowKey sets a function returning the key identifying a row, so that a
+// row can be matched with its previous version when the rows are replaced.
+// Without it, rows are matched by index. When the rows are sorted, replacing
+// them keeps the cursor on the row with the key of the selected one, and the
+// viewport scrolls to keep it visible.
+func WithRowKey(key func(Row) string) Option {
+	return func(m *Model) {
+		m.rowKey = key
+	}
+}
+
+// WithDiffHighlight sets whether the cells whose value changed in the last
+// call to SetRows render with Styles.Changed, matching rows with the row key.
+// The cells of new rows are all highlighted. The highlight lasts until the
+// next call to SetRows; setting the same rows again clears it.
+func WithDiffHighlight(highlight bool) Option {
+	return func(m *Model) {
+		m.diffHighlight = highlight
+		if !highlight {
+			m.changed = nil
+		}
+	}
+}
+
+// WithBoolGlyphs sets the glyphs rendered in place of true and false values,
+// such as "☑" and "☐". They apply to the values of bool columns, and to cells
+// of any other column whose value is "true" or "false", ignoring case. Other
+// values render as they are. Bool columns render "✓" and "✗" by default.
+func WithBoolGlyphs(trueGlyph, falseGlyph string) Option {
+	return func(m *Model) {
+		m.trueGlyph, m.falseGlyph = trueGlyph, falseGlyph
+		m.boolGlyphs = true
+	}
+}
+
+// WithCollapseDuplicates sets the columns where a value equal to the one of
+// the row above renders blank, so that runs of equal values, like a sorted
+// category, show only on their first row and on the first visible row. The
+// stored rows are not modified.
+func WithCollapseDuplicates(cols []int) Option {
+	return func(m *Model) {
+		m.collapseDuplicates = make(map[int]bool, len(cols))
+		for _, col := range cols {
+			m.collapseDuplicates[col] = true
+		}
+	}
+}
+
+// WithBeforeRender sets a function called at the start of View to adjust the
+// model right before it is rendered, for example to recompute a derived
+// column. Since View has a value receiver, the function receives a pointer to
+// the copy of the model being rendered: its changes are reflected in the
+// rendered frame only, and are not kept by the model.
+func WithBeforeRender(fn func(m *Model)) Option {
+	return func(m *Model) {
+		m.beforeRender = fn
+	}
+}
+
+// WithTitle sets a title rendered above the table, styled with Styles.Title.
+func WithTitle(title string) Option {
+	return func(m *Model) {
+		m.title = title
+	}
+}
+
+// WithTitleCount sets whether the number of rows, as returned by RowCount, is
+// rendered after the title, like "Users (42)", styled with Styles.Badge.
+func WithTitleCount(count bool) Option {
+	return func(m *Model) {
+		m.titleCount = count
+	}
+}
+
+// WithCaption sets a function rendering a status line below the table, such
+// as "Showing 10 of 200". It is called on every render so it can reflect the
+// current state of the model, and is styled with Styles.Caption.
+func WithCaption(caption func(m Model) string) Option {
+	return func(m *Model) {
+		m.caption = caption
+	}
+}
+
+// SelectionChangedMsg is sent by Update, with WithChangeMessages, when the
+// selected row changes.
+type SelectionChangedMsg struct {
+	// Cursor is the index of the selected row among the visible rows.
+	Cursor int
+	// Row is the selected row, or nil if there is none.
+	Row Row
+}
+
+// ViewportChangedMsg is sent by Update, with WithChangeMessages, when the rows
+// scroll vertically.
+type ViewportChangedMsg struct {
+	// Start is the index of the first row scrolled into view.
+	Start int
+}
+
+// WithChangeMessages sets whether Update returns commands sending a
+// SelectionChangedMsg when the selected row changes and a ViewportChangedMsg
+// when the rows scroll.
+func WithChangeMessages(enabled bool) Option {
+	return func(m *Model) {
+		m.changeMessages = enabled
+	}
+}
+
+// changeCmds returns the commands sending the change messages for the changes
+// since the cursor was at cursor on the underlying row selected and the first
+// visible row was start.
+func (m Model) changeCmds(cursor, selected, start int) []tea.Cmd {
+	if !m.changeMessages {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if m.cursor != cursor || m.SelectedIndex() != selected {
+		msg := SelectionChangedMsg{Cursor: m.cursor, Row: m.SelectedRow()}
+		cmds = append(cmds, func() tea.Msg { return msg })
+	}
+	if m.start != start {
+		msg := ViewportChangedMsg{Start: m.start}
+		cmds = append(cmds, func() tea.Msg { return msg })
+	}
+	return cmds
+}
+
+// Update is the Bubble Tea update loop.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case scrollFrameMsg:
+		return m, m.scrollFrame(msg)
+	case filterDebounceMsg:
+		m.debouncedFilter(msg)
+		return m, nil
+	case relativeTimeMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		return m, m.RelativeTimeTick()
+	}
+	if !m.focus {
+		return m, nil
+	}
+
+	cursor, selected, start := m.cursor, m.SelectedIndex(), m.start
+	if m.filterState == Filtering {
+		cmds := []tea.Cmd{m.handleFiltering(msg)}
+		cmds = append(cmds, m.changeCmds(cursor, selected, start)...)
+		return m, tea.Batch(cmds...)
+	}
+
+	var cmds []tea.Cmd
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		cmds = append(cmds, m.handleMouse(msg))
+	case tea.KeyMsg:
+		km := m.activeKeyMap()
+		if a, ok := km.matchAction(msg); ok {
+			cmds = append(cmds, m.runAction(a))
+		}
+	}
+
+	cmds = append(cmds, m.changeCmds(cursor, selected, start)...)
+	cmds = append(cmds, m.animateScroll(start))
+	return m, tea.Batch(cmds...)
+}
+
+// Focused returns the focus state of the table.
+func (m Model) Focused() bool {
+	return m.focus
+}
+
+// Focus focuses the table, allowing the user to move around the rows and
+// interact.
+func (m *Model) Focus() {
+	m.focus = true
+}
+
+// Blur blurs the table, preventing selection or movement. If the user is
+// editing the filter, the query typed so far is applied.
+func (m *Model) Blur() {
+	m.focus = false
+	if m.filterState == Filtering {
+		m.acceptFilter()
+	}
+}
+
+// SetFocused focuses or blurs the table.
+func (m *Model) SetFocused(f bool) {
+	if f {
+		m.Focus()
+	} else {
+		m.Blur()
+	}
+}
+
+// View renders the component.
+//
+// Without columns, the rows are rendered without headers, with as many
+// columns as the longest row. Cells missing from shorter rows render as empty
+// cells. With neither columns nor cells in the rows, the table renders as an
+// empty string.
+//
+// The function set with WithBeforeRender is called first, on a copy of the
+// model.
+func (m Model) View() string {
+	if m.beforeRender != nil {
+		m.beforeRender(&m)
+	}
+	view := m.tableView()
+	if indicator := m.scrollIndicatorView(); indicator != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, indicator)
+	}
+	if m.filterState != Unfiltered {
+		view = lipgloss.JoinVertical(lipgloss.Left, m.FilterInput.View(), view)
+	}
+	if title := m.titleView(); title != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, title, view)
+	}
+	if m.caption != nil {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, m.styles.Caption.Render(m.caption(m)))
+	}
+	if m.inlineHelp {
+		if m.Help.Width == 0 {
+			m.Help.Width = lipgloss.Width(view)
+		}
+		view = lipgloss.JoinVertical(lipgloss.Left, view, m.HelpView())
+	}
+	return m.margin.Render(view)
+}
+
+// HeaderView renders the header of the table as View renders it above the
+// rows: the top border, then the titles and the border below them when there
+// are columns. It is empty with a row template.
+func (m Model) HeaderView() string {
+	if m.rowTemplate != nil {
+		return ""
+	}
+	lines := m.tableLines()
+	return strings.Join(lines[:min(len(lines), m.headerHeight())], "\n")
+}
+
+// BodyView renders the rows of the table in the viewport as View renders them
+// below the header, without the bottom border. With HeaderView, it lets the
+// header and the rows be laid out separately.
+func (m Model) BodyView() string {
+	lines := m.tableLines()
+	if m.rowTemplate != nil {
+		return strings.Join(lines, "\n")
+	}
+	if len(lines) <= m.headerHeight()+1 {
+		return ""
+	}
+	return strings.Join(lines[m.headerHeight():len(lines)-1], "\n")
+}
+
+// tableLines returns the lines of the table as View renders them, without the
+// title, filter, caption and help around it.
+func (m Model) tableLines() []string {
+	if m.beforeRender != nil {
+		m.beforeRender(&m)
+	}
+	return strings.Split(m.tableView(), "\n")
+}
+
+// ViewInBox renders the component clipped to a box of the given size: lines
+// are cut at width cells and the lines below height are dropped, while a
+// smaller view is padded with spaces. The result is exactly height lines of
+// width cells, whatever the width and height of the table.
+func (m Model) ViewInBox(width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	view := strings.Split(m.View(), "\n")
+	lines := make([]string, height)
+	for i := range lines {
+		var line string
+		if i < len(view) {
+			line = ansi.Truncate(view[i], width, "")
+		}
+		lines[i] = line + strings.Repeat(" ", width-ansi.StringWidth(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// titleView renders the title, followed by the row count if enabled.
+func (m Model) titleView() string {
+	var parts []string
+	if m.title != "" {
+		parts = append(parts, m.styles.Title.Render(m.title))
+	}
+	if m.titleCount {
+		parts = append(parts, m.styles.Badge.Render("("+strconv.Itoa(m.RowCount())+")"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// scrollIndicatorView renders the number of rows above and below the
+// viewport, or nothing if they all fit in it.
+func (m Model) scrollIndicatorView() string {
+	if !m.scrollIndicator || !m.paging {
+		return ""
+	}
+	last := m.start
+	for _, p := range m.renderPlan() {
+		if p.kind == dataRow {
+			last = max(last, p.row)
+		}
+	}
+	above := m.start - m.frozenCount()
+	below := m.numRows() - 1 - last
+	return m.styles.ScrollIndicator.Render("↑ " + strconv.Itoa(above) + "  ↓ " + strconv.Itoa(below))
+}
+
+// tableView renders the header and the visible rows.
+func (m Model) tableView() string {
+	if m.rowTemplate != nil {
+		return m.templateView()
+	}
+	return m.renderTable(m.border)
+}
+
+// renderTable renders the header and the visible rows with the given border.
+func (m Model) renderTable(border lipgloss.Border) string {
+	if m.numColumns() == 0 {
+		return ""
+	}
+	renderTable := lipglosstable.New().Border(border)
+
+	maxColumnWidths := m.getMaxColumnWidths()
+	columns := m.visibleColumns(maxColumnWidths)
+	m.fillWidth(maxColumnWidths, columns)
+	plan := m.renderPlan()
+	renderTable.StyleFunc(func(row, col int) lipgloss.Style {
+		return m.renderedStyle(row, col, columns, plan)
+	})
+	renderTable.Data(tableData{m: m, maxColumnWidths: maxColumnWidths, columns: columns, plan: plan})
+	renderTable.Headers(m.getRenderColumns(maxColumnWidths, columns)...)
+	if m.manualHeight != 0 {
+		// XXX +4 for borders, need to expose computeHeader from lipgloss Table
+		renderTable.Height(m.manualHeight + 4)
+	}
+	if m.manualWidth != 0 {
+		// XXX +2 for borders
+		renderTable.Width(m.manualWidth + 2)
+	}
+	view := renderTable.Render()
+	if m.verticalHeader && len(m.cols) > 0 {
+		view = m.verticalHeaders(view, border, maxColumnWidths, columns)
+	}
+	if m.fullWidthSelection && m.manualWidth != 0 && !m.cellSelection && !m.cursorUnderline {
+		view = m.highlightSelection(view, border, plan)
+	}
+	if m.manualHeight != 0 {
+		view = clipLines(view, m.headerHeight(), m.manualHeight)
+	}
+	return view
+}
+
+// highlightSelection renders the column borders of the lines of the selected
+// row in view with the Selected style, so that the highlight spans the width of
+// the table.
+func (m Model) highlightSelection(view string, border lipgloss.Border, plan []planRow) string {
+	if border.Left == "" {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	first := m.headerHeight()
+	for _, p := range plan {
+		if p.kind != dataRow {
+			first++
+			continue
+		}
+		if p.row != m.cursor {
+			first += m.rowHeight(p.row)
+			continue
+		}
+		for i := first; i < first+m.rowHeight(p.row) && i < len(lines); i++ {
+			line := lines[i]
+			if !strings.HasPrefix(line, border.Left) || !strings.HasSuffix(line, border.Right) {
+				continue
+			}
+			inner := line[len(border.Left) : len(line)-len(border.Right)]
+			inner = strings.ReplaceAll(inner, border.Left, m.styles.Selected.Render(border.Left))
+			lines[i] = border.Left + inner + border.Right
+		}
+		break
+	}
+	return strings.Join(lines, "\n")
+}
+
+// headerHeight returns the number of lines rendered above the first row: the
+// top border, and the lines of the headers with their border when there are
+// columns.
+func (m Model) headerHeight() int {
+	if len(m.cols) == 0 {
+		return 1
+	}
+	return 2 + m.headerLines() //nolint:mnd
+}
+
+// clipLines keeps at most height lines of the rows rendered between the
+// header and the bottom border, cutting off a row that does not fully fit.
+func clipLines(view string, headerHeight, height int) string {
+	lines := strings.Split(view, "\n")
+	if len(lines)-headerHeight-1 <= height {
+		return view
+	}
+	bottom := lines[len(lines)-1]
+	return strings.Join(append(lines[:headerHeight+height], bottom), "\n")
+}
+
+func (m Model) getRenderColumns(maxColumnWidths []int, visibleColumns []int) []string {
+	if len(m.cols) == 0 {
+		return nil
+	}
+	columns := make([]string, len(visibleColumns))
+	for i, c := range visibleColumns {
+		if c < 0 {
+			columns[i] = strings.Repeat(" ", m.gutterWidth(c))
+			continue
+		}
+		data := ansi.Truncate(m.headerTitle(c, maxColumnWidths[c]), maxColumnWidths[c], "…")
+		columns[i] = lipgloss.PlaceHorizontal(maxColumnWidths[c], m.headerAlignment(c), data)
+	}
+	return columns
+}
+
+// headerTitle returns the content of the header of column col, given its
+// width, before truncation.
+func (m Model) headerTitle(col, width int) string {
+	title := m.cols[col].Title
+	if m.headerRenderer != nil {
+		return m.headerRenderer(col, title, width)
+	}
+	var indicator string
+	if m.sorted != nil && col == m.sortColumn {
+		indicator = sortIndicator(m.sortDesc)
+	}
+	if abbrev := m.cols[col].Abbrev; abbrev != "" && !m.verticalHeader && ansi.StringWidth(title+indicator) > width {
+		title = abbrev
+	}
+	return title + indicator
+}
+
+// verticalHeaders replaces the header line of the rendered table in view with
+// the titles stacked one character per line. Each line is rendered as the
+// header of a table without rows so that it gets the borders and the styles
+// of the header.
+func (m Model) verticalHeaders(view string, border lipgloss.Border, maxColumnWidths, columns []int) string {
+	lines := strings.Split(view, "\n")
+	if len(lines) < 2 { //nolint:mnd
+		return view
+	}
+	titles := make([][]rune, len(columns))
+	for i, c := range columns {
+		if c >= 0 {
+			titles[i] = []rune(ansi.Strip(m.headerTitle(c, maxColumnWidths[c])))
+		}
+	}
+	header := make([]string, m.headerLines())
+	for k := range header {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			if c < 0 {
+				cells[i] = strings.Repeat(" ", m.gutterWidth(c))
+				continue
+			}
+			var char string
+			if k < len(titles[i]) {
+				char = string(titles[i][k])
+			}
+			cells[i] = lipgloss.PlaceHorizontal(maxColumnWidths[c], m.headerAlignment(c), char)
+		}
+		headerTable := lipglosstable.New().Border(border).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				return m.renderedStyle(row, col, columns, nil)
+			}).
+			Headers(cells...)
+		if m.manualWidth != 0 {
+			headerTable.Width(m.manualWidth + 2) //nolint:mnd
+		}
+		header[k] = strings.Split(headerTable.Render(), "\n")[1]
+	}
+	return strings.Join(slices.Concat(lines[:1], header, lines[2:]), "\n")
+}
+
+// headerLines returns the number of lines the titles of the columns take.
+func (m Model) headerLines() int {
+	if !m.verticalHeader {
+		return 1
+	}
+	lines := 1
+	for c := range m.cols {
+		lines = max(lines, utf8.RuneCountInString(ansi.Strip(m.headerTitle(c, m.cols[c].Width))))
+	}
+	return lines
+}
+
+// Indexes of the rendered columns that are not part of the data.
+const (
+	lineNumberColumn = -1 - iota
+	indicatorColumn
+	iconColumn
+)
+
+// visibleColumns returns the indexes of the rendered columns: the gutters and
+// the row label, followed by the columns scrolled into view.
+func (m Model) visibleColumns(maxColumnWidths []int) []int {
+	columns := m.columnsFrom(m.firstScrolledColumn(maxColumnWidths), maxColumnWidths)
+	if m.responsiveHide && m.manualWidth != 0 {
+		columns = m.dropColumns(columns, maxColumnWidths)
+	}
+	return columns
+}
+
+// dropColumns removes the rendered columns of the lowest priority, the last
+// one first among equal priorities, until the others fit the width of the
+// table. The last column of the data is never removed.
+func (m Model) dropColumns(columns, maxColumnWidths []int) []int {
+	columns = slices.Clone(columns)
+	for m.columnsWidth(columns, maxColumnWidths) > m.manualWidth {
+		drop, data := -1, 0
+		for i, c := range columns {
+			if c < 0 {
+				continue
+			}
+			data++
+			if drop < 0 || m.columnPriority(c) <= m.columnPriority(columns[drop]) {
+				drop = i
+			}
+		}
+		if data <= 1 {
+			break
+		}
+		columns = slices.Delete(columns, drop, drop+1)
+	}
+	return columns
+}
+
+// columnPriority returns the priority of column col.
+func (m Model) columnPriority(col int) int {
+	if col < len(m.cols) {
+		return m.cols[col].Priority
+	}
+	return 0
+}
+
+// columnsFrom returns the indexes of the rendered columns when the first
+// column scrolled into view is first.
+func (m Model) columnsFrom(first int, maxColumnWidths []int) []int {
+	columns := m.fixedColumns()
+	width := m.columnsWidth(columns, maxColumnWidths)
+	for c := first; c < len(maxColumnWidths); c++ {
+		if m.scrolling() {
+			w := m.columnWidth(c, maxColumnWidths)
+			if len(columns) > 0 {
+				w++ // border
+			}
+			if c > first && width+w > m.manualWidth {
+				break
+			}
+			width += w
+		}
+		columns = append(columns, c)
+	}
+	return columns
+}
+
+// fixedColumns returns the indexes of the rendered columns that are not
+// scrolled horizontally.
+func (m Model) fixedColumns() []int {
+	var columns []int
+	if m.indicator != "" {
+		columns = append(columns, indicatorColumn)
+	}
+	if m.lineNumbers {
+		columns = append(columns, lineNumberColumn)
+	}
+	if m.rowIcon != nil {
+		columns = append(columns, iconColumn)
+	}
+	return append(columns, columnRange(0, m.frozenColumns())...)
+}
+
+// firstScrolledColumn returns the first column rendered after the row label,
+// keeping the column cursor in view.
+func (m Model) firstScrolledColumn(maxColumnWidths []int) int {
+	frozen := m.frozenColumns()
+	start := max(m.colStart, frozen)
+	if !m.scrolling() {
+		return frozen
+	}
+	if m.colCursor < frozen {
+		return start
+	}
+	start = min(start, m.colCursor)
+	for start < m.colCursor {
+		columns := append(m.fixedColumns(), columnRange(start, m.colCursor+1)...)
+		if m.columnsWidth(columns, maxColumnWidths) <= m.manualWidth {
+			break
+		}
+		start++
+	}
+	return start
+}
+
+// EnsureColumnVisible scrolls the columns horizontally so that column col is
+// fully visible next to the frozen columns, scrolling as little as possible.
+// If the focused column scrolls out of view, the closest visible column is
+// focused instead.
+func (m *Model) EnsureColumnVisible(col int) {
+	frozen := m.frozenColumns()
+	if !m.scrolling() || col < frozen || col >= m.numColumns() {
+		return
+	}
+	maxColumnWidths := m.getMaxColumnWidths()
+	start := min(max(m.colStart, frozen), col)
+	for start < col {
+		columns := append(m.fixedColumns(), columnRange(start, col+1)...)
+		if m.columnsWidth(columns, maxColumnWidths) <= m.manualWidth {
+			break
+		}
+		start++
+	}
+	m.colStart = start
+	if m.colCursor >= frozen {
+		columns := m.columnsFrom(start, maxColumnWidths)
+		m.colCursor = clamp(m.colCursor, start, columns[len(columns)-1])
+	}
+}
+
+// columnRange returns the column indexes from start up to, but excluding, end.
+func columnRange(start, end int) []int {
+	columns := make([]int, 0, max(0, end-start))
+	for c := start; c < end; c++ {
+		columns = append(columns, c)
+	}
+	return columns
+}
+
+// renderedStyle returns the style of the cell at the given rendered row and
+// column, given the visible columns and the rendered rows.
+func (m Model) renderedStyle(row, col int, columns []int, plan []planRow) lipgloss.Style {
+	if row == lipglosstable.HeaderRow {
+		return m.columnStyle(row, columns[col])
+	}
+	switch plan[row].kind {
+	case fillerRow:
+		return m.styles.Filler
+	case skeletonRow:
+		return m.styles.Skeleton
+	case groupDividerRow:
+		return m.styles.GroupDivider
+	case separatorRow:
+		return m.styles.Separator
+	}
+	style := m.columnStyle(plan[row].row, columns[col])
+	if !m.columnStripes {
+		return style
+	}
+	gutters := 0
+	for gutters < len(columns) && columns[gutters] < 0 {
+		gutters++
+	}
+	switch {
+	case col < gutters:
+		return style
+	case (col-gutters)%2 == 1:
+		return style.Inherit(m.styles.ColOdd)
+	default:
+		return style.Inherit(m.styles.ColEven)
+	}
+}
+
+// columnStyle returns the style of a rendered cell, which is given by the
+// StyleFunc and the column padding for the cells of the data.
+func (m Model) columnStyle(row, col int) lipgloss.Style {
+	switch col {
+	case lineNumberColumn:
+		if row == lipglosstable.HeaderRow {
+			return m.styles.Header
+		}
+		return m.styles.LineNumber
+	case indicatorColumn:
+		return m.styles.Indicator
+	case iconColumn:
+		return m.styles.Icon
+	default:
+		style := m.styleFunc(m, row, col)
+		if row >= 0 && row < m.numRows() && m.changed[m.rowIndex(row)][col] {
+			style = inheritStyle(m.styles.Changed, style)
+		}
+		if scale, ok := m.colorScales[col]; ok && row >= 0 && row < m.numRows() {
+			if v, ok := parseNumber(m.transformedValue(row, col)); ok {
+				style = style.Inherit(lipgloss.NewStyle().Foreground(scale(v)))
+			}
+		}
+		if m.cursorUnderline && row == m.cursor {
+			style = inheritStyle(m.styles.CursorLine, style)
+		}
+		if p, ok := m.columnPaddings[col]; ok {
+			return style.PaddingLeft(p.left).PaddingRight(p.right)
+		}
+		if m.padding != nil {
+			return style.PaddingLeft(m.padding.left).PaddingRight(m.padding.right)
+		}
+		return style
+	}
+}
+
+// gutterWidth returns the width of the content of a gutter column.
+func (m Model) gutterWidth(col int) int {
+	switch col {
+	case lineNumberColumn:
+		if m.relativeLineNumbers {
+			return len(strconv.Itoa(max(1, m.viewportRows())))
+		}
+		return len(strconv.Itoa(max(1, len(m.rows))))
+	case indicatorColumn:
+		return ansi.StringWidth(m.indicator)
+	case iconColumn:
+		var width int
+		for i := range m.rows {
+			width = max(width, ansi.StringWidth(m.rowIcon(i)))
+		}
+		return width
+	default:
+		return 0
+	}
+}
+
+// gutterValue returns the content of a gutter column for the visible row at
+// index i.
+func (m Model) gutterValue(i, col int) string {
+	switch col {
+	case lineNumberColumn:
+		n := m.rowIndex(i) + 1
+		if m.relativeLineNumbers {
+			n = m.renderedPosition(i) + 1
+		}
+		number := strconv.Itoa(n)
+		return strings.Repeat(" ", max(0, m.gutterWidth(col)-len(number))) + number
+	case indicatorColumn:
+		if i == m.cursor {
+			return m.indicator
+		}
+		return strings.Repeat(" ", m.gutterWidth(col))
+	case iconColumn:
+		icon := m.rowIcon(m.rowIndex(i))
+		return icon + strings.Repeat(" ", max(0, m.gutterWidth(col)-ansi.StringWidth(icon)))
+	default:
+		return ""
+	}
+}
+
+// scrolling reports whether columns that do not fit are scrolled.
+func (m Model) scrolling() bool {
+	return m.horizontalScroll && m.manualWidth != 0
+}
+
+// frozenColumns returns the number of leading columns that are not scrolled.
+func (m Model) frozenColumns() int {
+	if m.rowLabel && m.numColumns() > 0 {
+		return 1
+	}
+	return 0
+}
+
+// columnsWidth returns the width the given columns take when rendered,
+// including the borders between them.
+func (m Model) columnsWidth(columns []int, maxColumnWidths []int) int {
+	width := max(0, len(columns)-1)
+	for _, c := range columns {
+		width += m.columnWidth(c, maxColumnWidths)
+	}
+	return width
+}
+
+// columnWidth returns the width a column takes when rendered, including the
+// padding of its styles.
+func (m Model) columnWidth(col int, maxColumnWidths []int) int {
+	if col < 0 {
+		return m.gutterWidth(col) + m.columnStyle(0, col).GetHorizontalFrameSize()
+	}
+	padding := m.columnStyle(lipglosstable.HeaderRow, col).GetHorizontalFrameSize()
+	if m.numRows() > 0 {
+		padding = max(padding, m.columnStyle(m.start, col).GetHorizontalFrameSize())
+	}
+	return maxColumnWidths[col] + padding
+}
+
+func (m Model) getMaxColumnWidths() []int {
+	numColumns := m.numColumns()
+	maxColumnWidths := make([]int, numColumns)
+	contentWidths := make([]int, numColumns)
+	for i, col := range m.cols {
+		maxColumnWidths[i] = col.Width
+	}
+	for r, row := range m.rows {
+		for i, col := range m.transformRow(row) {
+			if i >= numColumns {
+				break
+			}
+			if i < len(m.cols) && m.cols[i].Width != 0 && m.truncateTolerance <= 0 {
+				continue
+			}
+			width := lipgloss.Width(m.displayValue(m.formatValue(col, i)))
+			if m.tree != nil && i == 0 {
+				width += m.treePrefixWidth(r)
+			}
+			contentWidths[i] = max(contentWidths[i], width)
+		}
+	}
+	for i := range maxColumnWidths {
+		if i < len(m.cols) && m.cols[i].Width != 0 {
+			continue
+		}
+		maxColumnWidths[i] = max(maxColumnWidths[i], contentWidths[i])
+		if minWidth := m.columnMinWidth(i); minWidth > 0 {
+			maxColumnWidths[i] = max(maxColumnWidths[i], minWidth)
+		}
+		if maxWidth := m.columnMaxWidth(i); maxWidth > 0 {
+			maxColumnWidths[i] = min(maxColumnWidths[i], maxWidth)
+		}
+	}
+	for i, width := range contentWidths {
+		// Widen the columns whose values overflow them by at most the
+		// tolerance rather than truncating the values.
+		if overflow := width - maxColumnWidths[i]; overflow > 0 && overflow <= m.truncateTolerance {
+			maxColumnWidths[i] = width
+		}
+	}
+	return maxColumnWidths
+}
+
+// columnMinWidth returns the minimum width of a column fitting its content.
+func (m Model) columnMinWidth(col int) int {
+	if col < len(m.minWidths) && m.minWidths[col] >= 0 {
+		return m.minWidths[col]
+	}
+	if col < len(m.cols) {
+		return m.cols[col].MinWidth
+	}
+	return 0
+}
+
+// columnMaxWidth returns the maximum width of a column fitting its content.
+func (m Model) columnMaxWidth(col int) int {
+	if col < len(m.maxWidths) && m.maxWidths[col] >= 0 {
+		return m.maxWidths[col]
+	}
+	if col < len(m.cols) {
+		return m.cols[col].MaxWidth
+	}
+	return 0
+}
+
+// HelpView is a helper method for rendering the help menu from the keymap.
+// Note that this view is not rendered by default and you must call it
+// manually in your application, where applicable.
+func (m Model) HelpView() string {
+	return m.Help.View(m.activeKeyMap())
+}
+
+// HelpBindings returns the key bindings of the table for rendering the help
+// elsewhere, leaving out the bindings that are currently disabled.
+func (m Model) HelpBindings() help.KeyMap {
+	active := m.activeKeyMap()
+	km := enabledKeyMap{short: enabledBindings(active.ShortHelp())}
+	for _, group := range active.FullHelp() {
+		if group = enabledBindings(group); len(group) > 0 {
+			km.full = append(km.full, group)
+		}
+	}
+	return km
+}
+
+// enabledKeyMap is a help.KeyMap holding only enabled bindings.
+type enabledKeyMap struct {
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func (km enabledKeyMap) ShortHelp() []key.Binding  { return km.short }
+func (km enabledKeyMap) FullHelp() [][]key.Binding { return km.full }
+
+func enabledBindings(bindings []key.Binding) []key.Binding {
+	var enabled []key.Binding
+	for _, b := range bindings {
+		if b.Enabled() {
+			enabled = append(enabled, b)
+		}
+	}
+	return enabled
+}
+
+// SelectedRow returns the selected row.
+// You can cast it to your own implementation.
+func (m Model) SelectedRow() Row {
+	if m.cursor < 0 || m.cursor >= m.numRows() {
+		return nil
+	}
+
+	return m.rows[m.rowIndex(m.cursor)]
+}
+
+// Rows returns the current rows, including the ones hidden by a filter.
+func (m Model) Rows() []Row {
+	return m.rows
+}
+
+// EachRow calls fn with the index and the value of each row, in the order they
+// were set, including the ones hidden by a filter, until fn returns false.
+func (m Model) EachRow(fn func(i int, r Row) bool) {
+	for i, row := range m.rows {
+		if !fn(i, row) {
+			return
+		}
+	}
+}
+
+// ColumnValues returns the values of column col in all the rows, in the order
+// they were set, including the ones hidden by a filter. Rows without a value
+// in the column give an empty string.
+func (m Model) ColumnValues(col int) []string {
+	values := make([]string, len(m.rows))
+	for i, row := range m.rows {
+		values[i] = cellAt(row, col)
+	}
+	return values
+}
+
+// RowCount returns the number of rows the cursor can move over: the rows
+// matching the filter when the rows are filtered, excluding the rows hidden in
+// collapsed trees.
+func (m Model) RowCount() int {
+	return m.numRows()
+}
+
+// Columns returns the current columns.
+func (m Model) Columns() []Column {
+	return m.cols
+}
+
+// CellWhere returns the value at column col of the first row for which pred
+// returns true. The second return value is false when no row matches or the
+// matching row has no such column.
+func (m Model) CellWhere(pred func(Row) bool, col int) (string, bool) {
+	if col < 0 {
+		return "", false
+	}
+	for _, row := range m.rows {
+		if !pred(row) {
+			continue
+		}
+		if col >= len(row) {
+			return "", false
+		}
+		return row[col], true
+	}
+	return "", false
+}
+
+// SetRows sets a new rows state.
+func (m *Model) SetRows(r []Row) {
+	previous := m.rows
+	var selected string
+	keyed := m.sorted != nil && m.rowKey != nil && m.SelectedIndex() >= 0
+	if keyed {
+		selected = m.rowKey(m.rows[m.SelectedIndex()])
+	}
+	m.rows = r
+	m.changed = nil
+	if m.diffHighlight && len(previous) > 0 {
+		m.changed = m.changedCells(previous)
+	}
+	if m.sorted != nil {
+		m.sortRows()
+	}
+	if m.filterState != Unfiltered {
+		m.applyFilter()
+	} else {
+		m.updateVisibleRows()
+		m.cursor = clamp(m.cursor, 0, max(0, m.numRows()-1))
+		m.onResize()
+	}
+	if keyed {
+		if i := slices.IndexFunc(m.rows, func(row Row) bool { return m.rowKey(row) == selected }); i >= 0 {
+			m.restoreCursor(i)
+		}
+	}
+	m.SetColCursor(m.colCursor)
+}
+
+// AppendRow adds a row after the last one.
+func (m *Model) AppendRow(row Row) {
+	m.AppendRows([]Row{row})
+}
+
+// AppendRows adds rows after the last one. With WithFollow, the cursor moves
+// to the last row if it was on the last row.
+func (m *Model) AppendRows(rows []Row) {
+	if len(rows) == 0 {
+		return
+	}
+	atBottom := m.cursor >= m.numRows()-1
+	m.SetRows(append(slices.Clip(m.rows), rows...))
+	if m.follow && atBottom {
+		m.GotoBottom()
+		return
+	}
+	m.onResize()
+}
+
+// SetColumns sets a new columns state. The rows are no longer sorted if the
+// column they are sorted by is removed.
+func (m *Model) SetColumns(c []Column) {
+	m.cols = c
+	if m.sorted != nil && m.sortColumn >= m.numColumns() {
+		m.ClearSort()
+	}
+	m.SetColCursor(m.colCursor)
+}
+
+// SetWidth sets the width of the viewport of the table.
+func (m *Model) SetWidth(w int) {
+	WithWidth(w)(m)
+}
+
+// SetHeight sets the height of the viewport of the table.
+func (m *Model) SetHeight(h int) {
+	WithHeight(h)(m)
+}
+
+// InsertSeparator renders a divider row above the row at the given index in
+// rows, with an optional label, styled with Styles.Separator. Separators are
+// not part of the data: the cursor skips them and SelectedRow never returns
+// one. A separator stays above its row index when the rows are sorted,
+// filtered or replaced, and is only rendered when its row is. Inserting a
+// separator above a row that already has one replaces its label.
+func (m *Model) InsertSeparator(index int, label string) {
+	if index < 0 || index >= len(m.rows) {
+		return
+	}
+	if m.separators == nil {
+		m.separators = make(map[int]string)
+	}
+	m.separators[index] = label
+	m.onResize()
+}
+
+// ClearSeparators removes all the separators.
+func (m *Model) ClearSeparators() {
+	m.separators = nil
+	m.onResize()
+}
+
+// separatorValue returns the content of a separator cell: a rule, starting
+// with the label in the first column.
+func separatorValue(label string, first bool, width int) string {
+	rule := ""
+	if first && label != "" {
+		rule = ansi.Truncate("── "+label+" ", width, "…")
+	}
+	return rule + strings.Repeat("─", max(0, width-lipgloss.Width(rule)))
+}
+
+// RowVisibility reports whether the row at the given index is rendered in the
+// viewport, and whether it is only partially visible because it is taller than
+// the lines left at the bottom of the viewport.
+func (m Model) RowVisibility(row int) (visible bool, partial bool) {
+	if row < 0 || row >= m.numRows() || (row >= m.frozenCount() && row < m.start) {
+		return false, false
+	}
+	if m.manualHeight == 0 && m.renderLimit == 0 {
+		return true, false
+	}
+	var lines, entries int
+	for n := 0; n < m.renderedPosition(row); n++ {
+		l, e := m.rowSpan(m.renderedRow(n))
+		lines += l
+		entries += e
+	}
+	// Count the separator rendered above the row, if any.
+	l, e := m.rowSpan(row)
+	lines += l - m.rowHeight(row)
+	entries += e - 1
+	if (m.manualHeight != 0 && lines >= m.manualHeight) || (m.renderLimit > 0 && entries >= m.renderLimit) {
+		return false, false
+	}
+	return true, m.manualHeight != 0 && lines+m.rowHeight(row) > m.manualHeight
+}
+
+// IsExpanded returns whether the visible row at the given index is expanded.
+func (m Model) IsExpanded(row int) bool {
+	if row < 0 || row >= m.numRows() {
+		return false
+	}
+	return m.expanded[m.rowIndex(row)]
+}
+
+// Expand expands the visible row at the given index, showing all the lines of
+// its cells. With accordion rows, the other rows of its group are collapsed.
+func (m *Model) Expand(row int) {
+	if row < 0 || row >= m.numRows() {
+		return
+	}
+	index := m.rowIndex(row)
+	if m.accordion {
+		group := m.rowGroup(index)
+		for i := range m.expanded {
+			if m.rowGroup(i) == group {
+				delete(m.expanded, i)
+			}
+		}
+	}
+	if m.expanded == nil {
+		m.expanded = map[int]bool{}
+	}
+	m.expanded[index] = true
+	m.updateTreeRows()
+}
+
+// Collapse collapses the visible row at the given index.
+func (m *Model) Collapse(row int) {
+	if row < 0 || row >= m.numRows() {
+		return
+	}
+	delete(m.expanded, m.rowIndex(row))
+	m.updateTreeRows()
+}
+
+// ToggleExpanded expands the visible row at the given index if it is
+// collapsed, and collapses it otherwise.
+func (m *Model) ToggleExpanded(row int) {
+	if m.IsExpanded(row) {
+		m.Collapse(row)
+	} else {
+		m.Expand(row)
+	}
+}
+
+func (m Model) rowGroup(index int) string {
+	if m.groupFunc == nil {
+		return ""
+	}
+	return m.groupFunc(index)
+}
+
+// Height returns the viewport height of the table.
+func (m Model) Height() int {
+	if m.manualHeight != 0 {
+		return m.manualHeight
+	} else {
+		return m.numRows()
+	}
+}
+
+// SetMovementStep sets the number of rows the LineUp and LineDown key bindings
+// move the cursor by.
+func (m *Model) SetMovementStep(n int) {
+	WithMovementStep(n)(m)
+}
+
+// movementStep returns the number of rows the LineUp and LineDown key
+// bindings move the cursor by.
+func (m Model) movementStep() int {
+	return max(1, m.step)
+}
+
+// PageSize returns the number of rows the page key bindings move the cursor
+// by: the page size set with WithPageSize, or the height of the viewport.
+func (m Model) PageSize() int {
+	if m.pageSize > 0 {
+		return m.pageSize
+	}
+	return m.Height()
+}
+
+// Cursor returns the index of the selected row among the visible rows: the
+// rows matching the filter, in the order they are sorted. The navigation
+// methods move it over the visible rows only. See SelectedIndex for the index
+// of the selected row in Rows.
+func (m Model) Cursor() int {
+	return m.cursor
+}
+
+// SetCursor sets the cursor position in the table.
+func (m *Model) SetCursor(n int) {
+	m.cursor = clamp(n, 0, m.numRows()-1)
+	m.onResize()
+}
+
+// MoveUp moves the selection up by any number of rows.
+// It can not go above the first row.
+func (m *Model) MoveUp(n int) {
+	m.cursor = clamp(m.cursor-n, 0, m.numRows()-1)
+	m.onResize()
+}
+
+// MoveDown moves the selection down by any number of rows.
+// It can not go below the last row.
+func (m *Model) MoveDown(n int) {
+	m.cursor = clamp(m.cursor+n, 0, m.numRows()-1)
+	m.onResize()
+}
+
+// ColCursor returns the index of the focused column. Together with Cursor it
+// identifies the focused cell, which a StyleFunc can use to highlight it.
+func (m Model) ColCursor() int {
+	return m.colCursor
+}
+
+// SetColCursor sets the focused column. When the column is hidden with
+// WithResponsiveHide, the closest rendered column in the direction of the move
+// is focused instead.
+func (m *Model) SetColCursor(n int) {
+	col := clamp(n, 0, max(0, m.numColumns()-1))
+	m.colCursor = m.closestRenderedColumn(col, n >= m.colCursor)
+	m.colStart = m.firstScrolledColumn(m.getMaxColumnWidths())
+}
+
+// MoveLeft moves the column focus left by any number of columns.
+// It can not go before the first column.
+func (m *Model) MoveLeft(n int) {
+	m.moveColumns(-n)
+}
+
+// MoveRight moves the column focus right by any number of columns.
+// It can not go past the last column.
+func (m *Model) MoveRight(n int) {
+	m.moveColumns(n)
+}
+
+// moveColumns moves the column focus by n rendered columns, stepping over the
+// columns hidden with WithResponsiveHide.
+func (m *Model) moveColumns(n int) {
+	rendered := m.renderedColumns()
+	i := slices.Index(rendered, m.colCursor)
+	if i < 0 {
+		m.SetColCursor(m.colCursor + n)
+		return
+	}
+	m.SetColCursor(rendered[clamp(i+n, 0, len(rendered)-1)])
+}
+
+// moveToEdgeColumn focuses the last column, or the first one, scrolling it
+// into view. When the column is hidden with WithResponsiveHide, the closest
+// rendered column is focused instead.
+func (m *Model) moveToEdgeColumn(last bool) {
+	if last {
+		m.SetColCursor(m.numColumns() - 1)
+	} else {
+		m.SetColCursor(0)
+	}
+}
+
+// renderedColumns returns the indexes of the columns of the data that are not
+// hidden with WithResponsiveHide. With horizontal scrolling, the columns
+// scrolled out of view are not hidden: focusing them scrolls them into view.
+func (m Model) renderedColumns() []int {
+	var columns []int
+	if m.responsiveHide && m.manualWidth != 0 && !m.scrolling() {
+		for _, c := range m.visibleColumns(m.getMaxColumnWidths()) {
+			if c >= 0 {
+				columns = append(columns, c)
+			}
+		}
+		return columns
+	}
+	for c := 0; c < m.numColumns(); c++ {
+		columns = append(columns, c)
+	}
+	return columns
+}
+
+// closestRenderedColumn returns column col if it is rendered, or else the
+// closest rendered column after it if after is set, or before it otherwise,
+// falling back to the other side.
+func (m Model) closestRenderedColumn(col int, after bool) int {
+	rendered := m.renderedColumns()
+	if len(rendered) == 0 || slices.Contains(rendered, col) {
+		return col
+	}
+	i, _ := slices.BinarySearch(rendered, col)
+	if (after && i < len(rendered)) || i == 0 {
+		return rendered[i]
+	}
+	return rendered[i-1]
+}
+
+// JumpToNextInColumn moves the selection to the next row whose value in
+// column col satisfies pred, and returns whether there is one.
+func (m *Model) JumpToNextInColumn(col int, pred func(string) bool) bool {
+	for i := m.cursor + 1; i < m.numRows(); i++ {
+		if pred(m.rawValue(i, col)) {
+			m.SetCursor(i)
+			return true
+		}
+	}
+	return false
+}
+
+// JumpToPrevInColumn moves the selection to the previous row whose value in
+// column col satisfies pred, and returns whether there is one.
+func (m *Model) JumpToPrevInColumn(col int, pred func(string) bool) bool {
+	for i := min(m.cursor, m.numRows()) - 1; i >= 0; i-- {
+		if pred(m.rawValue(i, col)) {
+			m.SetCursor(i)
+			return true
+		}
+	}
+	return false
+}
+
+// GotoTop moves the selection to the first row.
+func (m *Model) GotoTop() {
+	m.MoveUp(m.cursor)
+}
+
+// GotoBottom moves the selection to the last row.
+func (m *Model) GotoBottom() {
+	m.MoveDown(m.numRows())
+}
+
+// ResetView clears the sort and the filter, and moves the cursors and the
+// viewport back to the first row and column. The rows are kept. The ResetView
+// key binding is only enabled while the rows are sorted or filtered.
+func (m *Model) ResetView() {
+	m.resetFiltering()
+	m.sorted = nil
+	m.updateVisibleRows()
+	m.cursor, m.start, m.colStart = 0, 0, 0
+	m.SetColCursor(0)
+	m.onResize()
+	m.updateKeybindings()
+}
+
+// ScrollTo selects the visible row at index row and scrolls the viewport so
+// that it is rendered offset lines below the first scrolled line, 0 being the
+// top. The viewport is clamped so that it stays within the rows and the row is
+// fully visible.
+func (m *Model) ScrollTo(row, offset int) {
+	if m.numRows() == 0 {
+		return
+	}
+	m.cursor = clamp(row, 0, m.numRows()-1)
+	m.start = m.startAbove(m.cursor, offset)
+	m.onResize()
+}
+
+// startAbove returns the first visible row rendering the visible row at index
+// i offset lines below the first scrolled line, as far as the rows allow.
+func (m Model) startAbove(i, offset int) int {
+	frozen := m.frozenCount()
+	start := i
+	lines := len(m.rowsAbove(start))
+	for start > frozen {
+		l, _ := m.rowSpan(start - 1)
+		if lines+l > offset {
+			break
+		}
+		start--
+		lines += l
+	}
+	return min(start, m.maxStart())
+}
+
+func (m *Model) onResize() {
+	frozen := m.frozenCount()
+	if m.cursor < frozen {
+		m.start = clamp(m.start, frozen, max(frozen, m.numRows()-1))
+	} else {
+		if m.centerOnScroll && m.numRows() > 0 && (m.cursor < m.start || m.start < m.minStart()) {
+			m.start = m.startAbove(m.cursor, m.centerOffset())
+		}
+		m.start = clamp(m.start, max(m.minStart(), frozen), min(m.cursor, m.maxStart()))
+	}
+	if m.cursorBinding != nil {
+		*m.cursorBinding = m.cursor
+	}
+	m.updatePagingBindings()
+}
+
+// centerOffset returns the number of lines above the cursor row that center
+// it in the viewport.
+func (m Model) centerOffset() int {
+	height := m.manualHeight
+	if height == 0 {
+		height = m.renderLimit
+	}
+	lines, _ := m.rowSpan(m.cursor)
+	return max(0, (height-lines)/2)
+}
+
+// minStart returns the lowest first visible row that keeps the cursor row
+// fully visible.
+func (m Model) minStart() int {
+	return m.minStartFor(m.cursor)
+}
+
+// maxStart returns the highest first visible row that keeps the viewport
+// filled, that is the first one of the last page.
+func (m Model) maxStart() int {
+	frozen := m.frozenCount()
+	if m.numRows() == 0 {
+		return frozen
+	}
+	return max(frozen, m.minStartFor(m.numRows()-1))
+}
+
+// minStartFor returns the lowest first visible row that keeps the visible row
+// at index i fully visible.
+func (m Model) minStartFor(i int) int {
+	if (m.manualHeight == 0 && m.renderLimit == 0) || i >= m.numRows() {
+		return 0
+	}
+	frozen := m.frozenCount()
+	start := i
+	lines, entries := m.rowSpan(start)
+	for i := 0; i < frozen; i++ {
+		l, e := m.rowSpan(i)
+		lines += l
+		entries += e
+	}
+	for start > frozen {
+		l, e := m.rowSpan(start - 1)
+		if !m.fits(lines+l, entries+e) {
+			break
+		}
+		start--
+		lines += l
+		entries += e
+	}
+	return start
+}
+
+// fits reports whether the given number of rendered rows taking the given
+// number of lines fit in the viewport.
+func (m Model) fits(lines, rows int) bool {
+	return (m.manualHeight == 0 || lines <= m.manualHeight) &&
+		(m.renderLimit == 0 || rows <= m.renderLimit)
+}
+
+// RowRenderedHeight returns the number of lines the visible row at index row
+// takes when rendered, with its wrapped and expanded cells and the vertical
+// padding of its styles, or 0 if there is no such row. Dividers and separators
+// rendered above it are not counted.
+func (m Model) RowRenderedHeight(row int) int {
+	if row < 0 || row >= m.numRows() {
+		return 0
+	}
+	return m.rowHeight(row)
+}
+
+// rowHeight returns the number of lines the visible row at index i takes.
+func (m Model) rowHeight(i int) int {
+	height := 1
+	row := m.rows[m.rowIndex(i)]
+	for col := 0; col < min(len(row), m.numColumns()); col++ {
+		style := m.columnStyle(i, col)
+		lines := strings.Count(m.cellValue(i, col), "\n") + 1
+		height = max(height, lines+style.GetVerticalPadding()+style.GetVerticalMargins())
+	}
+	return height
+}
+
+// rowSpan returns the number of lines and of rendered rows the visible row at
+// index i takes, including the rows rendered above it.
+func (m Model) rowSpan(i int) (lines, rows int) {
+	above := len(m.rowsAbove(i))
+	return m.rowHeight(i) + above, 1 + above
+}
+
+// rowsAbove returns the rows that are not part of the data rendered right
+// above the visible row at index i, each one line tall: the group divider if
+// it starts a group, then its separator.
+func (m Model) rowsAbove(i int) []planRow {
+	var rows []planRow
+	if m.groupDividers && i > 0 && m.transformedValue(i, m.groupDividerCol) != m.transformedValue(i-1, m.groupDividerCol) {
+		rows = append(rows, planRow{kind: groupDividerRow})
+	}
+	if label, ok := m.separators[m.rowIndex(i)]; ok {
+		rows = append(rows, planRow{kind: separatorRow, label: label})
+	}
+	return rows
+}
+
+// planRowKind is the kind of a rendered row.
+type planRowKind int
+
+const (
+	dataRow planRowKind = iota
+	separatorRow
+	fillerRow
+	skeletonRow
+	groupDividerRow
+)
+
+// planRow is a row rendered in the viewport: a row of the data, or a row that
+// is not part of the data and can not be selected.
+type planRow struct {
+	kind planRowKind
+	// visible index of a data row.
+	row int
+	// label of a separator row.
+	label string
+}
+
+// renderPlan returns the rows rendered, at least partially, in the viewport.
+func (m Model) renderPlan() []planRow {
+	var plan []planRow
+	var lines int
+	full := func() bool {
+		return (m.manualHeight != 0 && lines >= m.manualHeight) ||
+			(m.renderLimit > 0 && len(plan) >= m.renderLimit)
+	}
+	if m.loading && m.skeletonRows > 0 {
+		for len(plan) < m.skeletonRows && !full() {
+			plan = append(plan, planRow{kind: skeletonRow})
+			lines++
+		}
+		return plan
+	}
+rows:
+	for n := 0; !full(); n++ {
+		i := m.renderedRow(n)
+		if i >= m.numRows() {
+			break
+		}
+		for _, p := range m.rowsAbove(i) {
+			plan = append(plan, p)
+			lines++
+			if full() {
+				break rows
+			}
+		}
+		plan = append(plan, planRow{kind: dataRow, row: i})
+		lines += m.rowHeight(i)
+	}
+	if m.fillHeight && m.manualHeight != 0 {
+		for !full() {
+			plan = append(plan, planRow{kind: fillerRow})
+			lines++
+		}
+	}
+	return plan
+}
+
+// viewportRows returns the number of data rows rendered, at least partially,
+// in the viewport.
+func (m Model) viewportRows() int {
+	var n int
+	for _, p := range m.renderPlan() {
+		if p.kind == dataRow {
+			n++
+		}
+	}
+	return n
+}
+
+// frozenCount returns the number of frozen rows that are rendered.
+func (m Model) frozenCount() int {
+	return min(m.frozenRows, m.numRows())
+}
+
+// renderedRow returns the visible index of the n-th rendered row: the frozen
+// rows first, then the rows from the first visible row.
+func (m Model) renderedRow(n int) int {
+	if frozen := m.frozenCount(); n >= frozen {
+		return m.start + n - frozen
+	}
+	return n
+}
+
+// renderedPosition is the inverse of renderedRow.
+func (m Model) renderedPosition(i int) int {
+	if frozen := m.frozenCount(); i >= frozen {
+		return i - m.start + frozen
+	}
+	return i
+}
+
+// prevRenderedRow returns the visible index of the row rendered right above
+// the row at index i, or -1 if it is the first rendered row.
+func (m Model) prevRenderedRow(i int) int {
+	frozen := m.frozenCount()
+	switch {
+	case i > m.start || (i > 0 && i < frozen):
+		return i - 1
+	case i == m.start && frozen > 0:
+		return frozen - 1
+	default:
+		return -1
+	}
+}
+
+// numRows returns the number of rows available for navigation.
+func (m Model) numRows() int {
+	if m.visible != nil {
+		return len(m.visible)
+	}
+	return len(m.rows)
+}
+
+// numColumns returns the number of rendered columns.
+func (m Model) numColumns() int {
+	if len(m.cols) > 0 {
+		return len(m.cols)
+	}
+	var n int
+	for _, row := range m.rows {
+		n = max(n, len(row))
+	}
+	return n
+}
+
+// rowIndex maps the index of a visible row to its index in rows.
+func (m Model) rowIndex(i int) int {
+	if m.visible != nil {
+		return m.visible[i]
+	}
+	return i
+}
+
+// updateVisibleRows computes the visible rows from the sort order, the filter
+// and the collapsed tree rows.
+func (m *Model) updateVisibleRows() {
+	m.visible = m.visibleRows()
+	m.updatePagingBindings()
+}
+
+// visibleRows returns the indexes in rows of the visible rows in the order
+// they are shown, or nil if all the rows are shown in their order.
+func (m Model) visibleRows() []int {
+	query := m.FilterInput.Value()
+	filtering := m.filterState != Unfiltered && query != ""
+	if m.sorted == nil && !filtering && m.tree == nil {
+		return nil
+	}
+	hidden := m.collapsedTreeRows()
+	visible := []int{}
+	for n := range m.rows {
+		i := n
+		if m.sorted != nil {
+			i = m.sorted[n]
+		}
+		if hidden[i] || (filtering && !m.matchesFilter(m.rows[i], query)) {
+			continue
+		}
+		visible = append(visible, i)
+	}
+	return visible
+}
+
+// UnderlyingIndex returns the index in Rows of the visible row at index
+// visible, as counted by Cursor, and whether there is such a row.
+func (m Model) UnderlyingIndex(visible int) (int, bool) {
+	if visible < 0 || visible >= m.numRows() {
+		return -1, false
+	}
+	return m.rowIndex(visible), true
+}
+
+// VisibleIndex returns the index among the visible rows, as counted by
+// Cursor, of the row at index underlying in Rows, and whether it is visible.
+func (m Model) VisibleIndex(underlying int) (int, bool) {
+	for i := 0; i < m.numRows(); i++ {
+		if m.rowIndex(i) == underlying {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// SelectedIndex returns the index in Rows of the selected row, whatever the
+// sort and the filter, or -1 if there is none.
+func (m Model) SelectedIndex() int {
+	if m.cursor < 0 || m.cursor >= m.numRows() {
+		return -1
+	}
+	return m.rowIndex(m.cursor)
+}
+
+// updateKeybindings enables the bindings of the enabled features relevant to the
+// current filter state. Navigation keys are not handled while filtering.
+func (m *Model) updateKeybindings() {
+	switch m.filterState {
+	case Filtering:
+		m.KeyMap.ToggleExpand.SetEnabled(false)
+		m.KeyMap.NextMatch.SetEnabled(false)
+		m.KeyMap.PrevMatch.SetEnabled(false)
+		m.KeyMap.Filter.SetEnabled(false)
+		m.KeyMap.ClearFilter.SetEnabled(false)
+		m.KeyMap.ToggleSort.SetEnabled(false)
+		m.KeyMap.ResetView.SetEnabled(false)
+		m.KeyMap.CancelWhileFiltering.SetEnabled(true)
+		m.KeyMap.AcceptWhileFiltering.SetEnabled(m.FilterInput.Value() != "")
+
+	case Unfiltered, FilterApplied:
+		m.KeyMap.ToggleExpand.SetEnabled(m.expandable || m.tree != nil)
+		m.KeyMap.NextMatch.SetEnabled(m.jumpFunc != nil)
+		m.KeyMap.PrevMatch.SetEnabled(m.jumpFunc != nil)
+		m.KeyMap.Filter.SetEnabled(m.filteringEnabled)
+		m.KeyMap.ClearFilter.SetEnabled(m.filterState == FilterApplied)
+		m.KeyMap.ToggleSort.SetEnabled(m.clickToSort)
+		m.KeyMap.ResetView.SetEnabled(m.sorted != nil || m.filterState == FilterApplied)
+		m.KeyMap.CancelWhileFiltering.SetEnabled(false)
+		m.KeyMap.AcceptWhileFiltering.SetEnabled(false)
+	}
+
+	for a := range m.disabledActions {
+		m.KeyMap.binding(a).SetEnabled(false)
+	}
+	m.updatePagingBindings()
+}
+
+// pagingActions are the actions moving the cursor by pages.
+var pagingActions = []Action{ActionPageUp, ActionPageDown, ActionHalfPageUp, ActionHalfPageDown}
+
+// updatePagingBindings enables the paging actions only when the visible rows
+// do not all fit in the viewport, so that they are hidden from the help of
+// short tables.
+func (m *Model) updatePagingBindings() {
+	m.paging = m.numRows() > 0 && m.maxStart() > m.frozenCount()
+}
+
+// activeKeyMap returns the key map with the paging bindings disabled while
+// the visible rows all fit in the viewport, leaving the bindings of KeyMap
+// as they were set.
+func (m Model) activeKeyMap() KeyMap {
+	km := m.KeyMap
+	if !m.paging {
+		for _, a := range pagingActions {
+			km.binding(a).SetEnabled(false)
+		}
+	}
+	return km
+}
+
+// FromValues create the table rows from a simple string. It uses `\n` by
+// default for getting all the rows and the given separator for the fields on
+// each row.
+func (m *Model) FromValues(value, separator string) {
+	rows := []Row{}
+	for _, line := range strings.Split(value, "\n") {
+		r := Row{}
+		for _, field := range strings.Split(line, separator) {
+			r = append(r, field)
+		}
+		rows = append(rows, r)
+	}
+
+	m.setImportedRows(rows)
+}
+
+// FromCSV creates the table rows from the CSV records read from r. Records may
+// have different numbers of fields. On error, the rows are left unchanged.
+func (m *Model) FromCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+	rows := make([]Row, len(records))
+	for i, record := range records {
+		rows[i] = record
+	}
+	m.setImportedRows(rows)
+	return nil
+}
+
+// setImportedRows sets the imported rows, taking the column titles from the
+// first one if the first row is a header.
+func (m *Model) setImportedRows(rows []Row) {
+	if m.firstRowHeader && len(rows) > 0 {
+		cols := make([]Column, len(rows[0]))
+		copy(cols, m.cols)
+		for i, title := range rows[0] {
+			cols[i].Title = title
+		}
+		m.SetColumns(cols)
+		rows = rows[1:]
+	}
+	m.SetRows(rows)
+}
+
+// cellValue returns the value of the cell of the visible row at index i as it
+// should be rendered, before truncation.
+func (m Model) cellValue(i, col int) string {
+	index := m.rowIndex(i)
+	value := m.transformedValue(i, col)
+	if prev := m.prevRenderedRow(i); m.collapseDuplicates[col] && prev >= 0 && value == m.transformedValue(prev, col) {
+		return ""
+	}
+	if delta, ok := m.deltaValue(index, col, value); ok {
+		value = m.displayValue(delta)
+	} else {
+		value = m.displayValue(m.formatValue(value, col))
+	}
+	if m.expandable && !m.expanded[index] {
+		value, _, _ = strings.Cut(value, "\n")
+	}
+	if width := m.wrapWidth(col); width > 0 {
+		if m.tree != nil && col == 0 {
+			width = max(1, width-m.treePrefixWidth(index))
+		}
+		value = ansi.Wrap(value, width, "")
+	}
+	if m.tree != nil && col == 0 {
+		value = m.treePrefix(index) + strings.ReplaceAll(value, "\n", "\n"+strings.Repeat(" ", m.treePrefixWidth(index)))
+	}
+	return value
+}
+
+// wrapWidth returns the width values of column col wrap at, or 0 if they do
+// not wrap. Columns fitting their content only need to wrap at their maximum
+// width.
+func (m Model) wrapWidth(col int) int {
+	if col < 0 || col >= len(m.cols) || !m.cols[col].Wrap {
+		return 0
+	}
+	if m.cols[col].Width != 0 {
+		return m.cols[col].Width
+	}
+	return m.columnMaxWidth(col)
+}
+
+// changedCells returns the columns of the cells of each row whose value
+// differs from the one of the matching row in previous.
+func (m Model) changedCells(previous []Row) map[int]map[int]bool {
+	matches := matchRows(previous, m.rows, m.rowKey)
+	changed := map[int]map[int]bool{}
+	for i, row := range m.rows {
+		for col, value := range row {
+			if p := matches[i]; p >= 0 && cellAt(previous[p], col) == value {
+				continue
+			}
+			if changed[i] == nil {
+				changed[i] = map[int]bool{}
+			}
+			changed[i][col] = true
+		}
+	}
+	return changed
+}
+
+// transformedValue returns the value of the cell of the visible row at index
+// i after the row transform.
+func (m Model) transformedValue(i, col int) string {
+	if m.rowTransform == nil {
+		return m.rawValue(i, col)
+	}
+	return cellAt(m.transformRow(m.rows[m.rowIndex(i)]), col)
+}
+
+// transformRow returns the row as transformed by the row transform. Rows
+// transformed into a different number of cells are left untouched.
+func (m Model) transformRow(row Row) Row {
+	if m.rowTransform == nil {
+		return row
+	}
+	if transformed := m.rowTransform(row); len(transformed) == len(row) {
+		return transformed
+	}
+	return row
+}
+
+// rawValue returns the stored value of the cell of the visible row at index i,
+// or an empty string if the row is too short.
+func (m Model) rawValue(i, col int) string {
+	row := m.rows[m.rowIndex(i)]
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
+
+// columnAlignment returns the alignment of column col.
+func (m Model) columnAlignment(col int) lipgloss.Position {
+	if col < 0 || col >= len(m.cols) {
+		return lipgloss.Left
+	}
+	switch c := m.cols[col]; {
+	case c.Alignment != lipgloss.Left:
+		return c.Alignment
+	case c.Type == NumberColumn, c.Type == DateColumn:
+		return lipgloss.Right
+	default:
+		return lipgloss.Left
+	}
+}
+
+// headerAlignment returns the alignment of the title of column col.
+func (m Model) headerAlignment(col int) lipgloss.Position {
+	if col >= 0 && col < len(m.cols) && m.cols[col].HeaderAlignment != lipgloss.Left {
+		return m.cols[col].HeaderAlignment
+	}
+	return m.columnAlignment(col)
+}
+
+// formatValue returns the value of a cell of column col formatted according
+// to the type of the column.
+func (m Model) formatValue(value string, col int) string {
+	if m.accountingColumns[col] {
+		if v, ok := parseNumber(value); ok && v < 0 {
+			return m.styles.Negative.Render("(" + strings.TrimPrefix(strings.TrimSpace(value), "-") + ")")
+		}
+	}
+	var b, ok bool
+	switch {
+	case m.columnType(col) == BoolColumn:
+		b, ok = parseBool(value)
+	case m.boolGlyphs && strings.EqualFold(value, "true"):
+		b, ok = true, true
+	case m.boolGlyphs && strings.EqualFold(value, "false"):
+		b, ok = false, true
+	}
+	switch {
+	case !ok:
+		return value
+	case b:
+		return m.trueGlyph
+	default:
+		return m.falseGlyph
+	}
+}
+
+// deltaValue returns the difference between the value of column col of the row
+// at index in rows and the one of the baseline row, signed and styled, and
+// whether the cell renders as a delta.
+func (m Model) deltaValue(index, col int, value string) (string, bool) {
+	if !m.deltaColumns[col] || m.baselineRow < 0 || m.baselineRow >= len(m.rows) || index == m.baselineRow {
+		return "", false
+	}
+	base := cellAt(m.transformRow(m.rows[m.baselineRow]), col)
+	v, ok := parseNumber(value)
+	b, baseOK := parseNumber(base)
+	if !ok || !baseOK {
+		return "", false
+	}
+	precision := max(decimals(value), decimals(base))
+	switch d := v - b; {
+	case d > 0:
+		return m.styles.Positive.Render("+" + strconv.FormatFloat(d, 'f', precision, 64)), true
+	case d < 0:
+		return m.styles.Negative.Render(strconv.FormatFloat(d, 'f', precision, 64)), true
+	default:
+		return strconv.FormatFloat(0, 'f', precision, 64), true
+	}
+}
+
+// decimals returns the number of digits after the decimal point of a number.
+func decimals(s string) int {
+	_, fraction, _ := strings.Cut(strings.TrimSpace(s), ".")
+	return len(fraction)
+}
+
+// displayValue returns the cell value as it should be rendered.
+func (m Model) displayValue(value string) string {
+	if value == "" {
+		return m.emptyCell
+	}
+	if m.stripAnsi {
+		value = ansi.Strip(value)
+	}
+	if m.tabWidth > 0 {
+		value = expandTabs(value, m.tabWidth)
+	}
+	return value
+}
+
+// expandTabs replaces tab characters with spaces up to the next tab stop,
+// measuring each line by its display width.
+func expandTabs(s string, tabWidth int) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		var b strings.Builder
+		segments := strings.Split(line, "\t")
+		for j, segment := range segments {
+			b.WriteString(segment)
+			if j < len(segments)-1 {
+				w := ansi.StringWidth(b.String())
+				b.WriteString(strings.Repeat(" ", tabWidth-w%tabWidth))
+			}
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func clamp(v, low, high int) int {
+	return min(max(v, low), high)
 }
 
 // Implements lipglosstable.Data without exposing it.
@@ -492,25 +3272,116 @@ type tableData struct {
 	m Model
 	// Space pad all of the rows so that when scrolling the width of the columns does not change.
 	maxColumnWidths []int
+	// indexes of the rendered columns. nil to render all of them.
+	columns []int
+	// rendered rows. nil to compute them from the model.
+	plan []planRow
 }
 
 var _ lipglosstable.Data = tableData{}
 
 func (t tableData) At(row, col int) string {
-	data := t.m.rows[t.m.start+row][col]
+	pos := col
+	first := col == 0
+	if t.columns != nil {
+		first = col == 0 || (t.columns[col] >= 0 && t.columns[col-1] < 0)
+		col = t.columns[col]
+	}
+	p := t.rowPlan()[row]
+	var width int
+	if col < 0 {
+		width = t.m.gutterWidth(col)
+	} else {
+		width = t.maxColumnWidths[col]
+	}
+	switch {
+	case p.kind == fillerRow, p.kind == skeletonRow && col < 0:
+		return strings.Repeat(" ", width)
+	case p.kind == skeletonRow:
+		return strings.Repeat(skeletonBar, width)
+	case p.kind == separatorRow:
+		return separatorValue(p.label, first && col >= 0, width)
+	case p.kind == groupDividerRow:
+		return separatorValue("", false, width)
+	case col < 0:
+		return t.m.gutterValue(p.row, col)
+	}
+	if t.m.overflow {
+		if line, ok := t.spill(p.row, pos); ok {
+			return lipgloss.PlaceHorizontal(width, lipgloss.Left, line)
+		}
+	}
+	data := t.m.cellValue(p.row, col)
+	if renderer, ok := t.m.cellRenderers[col]; ok && data != "" {
+		data = renderer(data, t.maxColumnWidths[col])
+	}
 	lines := strings.Split(data, "\n")
 	for i, line := range lines {
 		line = ansi.Truncate(line, t.maxColumnWidths[col], "…")
-		padding := strings.Repeat(" ", max(0, t.maxColumnWidths[col]-lipgloss.Width(line)))
-		lines[i] = line + padding
+		lines[i] = lipgloss.PlaceHorizontal(t.maxColumnWidths[col], t.m.columnAlignment(col), line)
 	}
 	return strings.Join(lines, "\n")
 }
 
+// spill returns the part of a value rendered in the cell at position pos of the
+// visible row i when it spills over into the following empty cells, and
+// whether it does.
+func (t tableData) spill(i, pos int) (string, bool) {
+	start := pos
+	for start > 0 && t.m.transformedValue(i, t.column(start)) == "" && t.column(start-1) >= 0 {
+		start--
+	}
+	source := t.column(start)
+	if _, ok := t.m.cellRenderers[source]; ok || t.m.transformedValue(i, source) == "" {
+		return "", false
+	}
+	line := t.m.cellValue(i, source)
+	if strings.Contains(line, "\n") {
+		return "", false
+	}
+
+	var offset int
+	for p := start; p < pos; p++ {
+		offset += t.maxColumnWidths[t.column(p)]
+	}
+	width := t.maxColumnWidths[t.column(pos)]
+	lineWidth := ansi.StringWidth(line)
+	if lineWidth <= offset+width && start == pos || lineWidth <= offset {
+		return "", false
+	}
+	next := pos + 1
+	if next < t.Columns() && t.column(next) >= 0 && t.m.transformedValue(i, t.column(next)) == "" {
+		return ansi.Cut(line, offset, offset+width), true
+	}
+	if start == pos {
+		return "", false
+	}
+	return ansi.Truncate(ansi.Cut(line, offset, lineWidth), width, "…"), true
+}
+
+// column returns the index of the column rendered at position pos.
+func (t tableData) column(pos int) int {
+	if t.columns != nil {
+		return t.columns[pos]
+	}
+	return pos
+}
+
 func (t tableData) Rows() int {
-	return min(t.m.Height(), len(t.m.rows)-t.m.start)
+	return len(t.rowPlan())
+}
+
+// rowPlan returns the rendered rows.
+func (t tableData) rowPlan() []planRow {
+	if t.plan != nil {
+		return t.plan
+	}
+	return t.m.renderPlan()
 }
 
 func (t tableData) Columns() int {
+	if t.columns != nil {
+		return len(t.columns)
+	}
 	return len(t.maxColumnWidths)
 }
@@ -1,12 +1,17 @@
 package table
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"text/template"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/charmbracelet/x/exp/golden"
+	"github.com/muesli/termenv"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -48,6 +53,37 @@ func TestFromValuesWithTabSeparator(t *testing.T) {
 	}
 }
 
+func TestFromCSVWithFirstRowHeader(t *testing.T) {
+	input := "Name,Age\nAlice,30\n\"Bob, Jr.\",4\n"
+	table := New(
+		WithColumns([]Column{{Title: "Foo", Width: 12}}),
+		WithFirstRowHeader(true),
+	)
+	if err := table.FromCSV(strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cols := table.Columns()
+	if len(cols) != 2 || cols[0].Title != "Name" || cols[1].Title != "Age" {
+		t.Fatalf("expected the titles from the first line, got %+v", cols)
+	}
+	if cols[0].Width != 12 {
+		t.Fatalf("expected the width of the existing column to be kept, got %d", cols[0].Width)
+	}
+	expect := []Row{
+		{"Alice", "30"},
+		{"Bob, Jr.", "4"},
+	}
+	if !deepEqual(table.rows, expect) {
+		t.Fatalf("expected rows %v, got %v", expect, table.rows)
+	}
+
+	table.FromValues("A|B\n1|2", "|")
+	if cols := table.Columns(); cols[0].Title != "A" || len(table.rows) != 1 {
+		t.Fatalf("expected FromValues to honor the header too, got %+v and %v", cols, table.rows)
+	}
+}
+
 func deepEqual(a, b []Row) bool {
 	if len(a) != len(b) {
 		return false
@@ -236,3 +272,3290 @@ func TestWrapCursor(t *testing.T) {
 		}
 	})
 }
+
+func TestTabWidth(t *testing.T) {
+	columns := []Column{{Title: "Value"}}
+	rows := []Row{{"a\tbc\td"}}
+
+	t.Run("tabs are expanded to the next tab stop", func(t *testing.T) {
+		model := New(WithColumns(columns), WithRows(rows), WithTabWidth(4))
+		td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+		got := td.At(0, 0)
+		if want := "a   bc  d"; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		if w := model.getMaxColumnWidths()[0]; w != 9 {
+			t.Fatalf("expected column width 9, got %d", w)
+		}
+	})
+	t.Run("stored rows keep their tabs", func(t *testing.T) {
+		model := New(WithColumns(columns), WithRows(rows), WithTabWidth(4))
+		if got := model.Rows()[0][0]; got != rows[0][0] {
+			t.Fatalf("expected stored value %q, got %q", rows[0][0], got)
+		}
+	})
+	t.Run("tabs are untouched by default", func(t *testing.T) {
+		model := New(WithColumns(columns), WithRows(rows))
+		if got := model.displayValue(rows[0][0]); got != rows[0][0] {
+			t.Fatalf("expected %q, got %q", rows[0][0], got)
+		}
+	})
+}
+
+func TestAnsiCellTruncation(t *testing.T) {
+	colored := "\x1b[31mRedRedRed\x1b[0m"
+	columns := []Column{{Title: "Color", Width: 5}}
+	rows := []Row{{colored}}
+
+	t.Run("embedded codes do not count towards the width", func(t *testing.T) {
+		model := New(WithColumns(columns), WithRows(rows))
+		td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+		got := td.At(0, 0)
+		if w := ansi.StringWidth(got); w != 5 {
+			t.Fatalf("expected display width 5, got %d: %q", w, got)
+		}
+		if want := "RedR…"; ansi.Strip(got) != want {
+			t.Fatalf("expected %q, got %q", want, ansi.Strip(got))
+		}
+	})
+	t.Run("codes are removed with WithStripAnsi", func(t *testing.T) {
+		model := New(WithColumns(columns), WithRows(rows), WithStripAnsi(true))
+		td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+		if got, want := td.At(0, 0), "RedR…"; got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		if got := model.Rows()[0][0]; got != colored {
+			t.Fatalf("expected stored value to keep its codes, got %q", got)
+		}
+	})
+}
+
+func TestCellWhere(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "ID"}, {Title: "Name"}}),
+		WithRows([]Row{
+			{"1", "Alice"},
+			{"2", "Bob"},
+			{"3"},
+		}),
+	)
+	byID := func(id string) func(Row) bool {
+		return func(r Row) bool { return len(r) > 0 && r[0] == id }
+	}
+
+	if got, ok := model.CellWhere(byID("2"), 1); !ok || got != "Bob" {
+		t.Fatalf("expected (Bob, true), got (%q, %t)", got, ok)
+	}
+	if got, ok := model.CellWhere(byID("4"), 1); ok {
+		t.Fatalf("expected no match, got %q", got)
+	}
+	if got, ok := model.CellWhere(byID("3"), 1); ok {
+		t.Fatalf("expected no value for a short row, got %q", got)
+	}
+	if got, ok := model.CellWhere(byID("1"), -1); ok {
+		t.Fatalf("expected no value for a negative column, got %q", got)
+	}
+}
+
+func TestCaption(t *testing.T) {
+	caption := func(m Model) string {
+		return fmt.Sprintf("Showing %d rows", len(m.Rows()))
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 10}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}}),
+		WithCaption(caption),
+	)
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	if got := strings.TrimRight(lines[len(lines)-1], " "); got != "Showing 2 rows" {
+		t.Fatalf("expected caption on the last line, got %q", got)
+	}
+
+	model.SetRows([]Row{{"Alice"}, {"Bob"}, {"Carol"}})
+	lines = strings.Split(ansi.Strip(model.View()), "\n")
+	if got := strings.TrimRight(lines[len(lines)-1], " "); got != "Showing 3 rows" {
+		t.Fatalf("expected caption to reflect the new rows, got %q", got)
+	}
+}
+
+func TestClearFilterWithEscape(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 10}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Carol"}, {"Bobby"}}),
+		WithFocused(true),
+		WithFiltering(true),
+	)
+	keys := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	model, _ = model.Update(keys("/"))
+	if !model.SettingFilter() {
+		t.Fatalf("expected to be setting the filter, state is %s", model.FilterState())
+	}
+	model, _ = model.Update(keys("bob"))
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !model.IsFiltered() {
+		t.Fatalf("expected filter to be applied, state is %s", model.FilterState())
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := model.SelectedRow(); len(got) == 0 || got[0] != "Bobby" {
+		t.Fatalf("expected Bobby to be selected, got %v", got)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.FilterState() != Unfiltered {
+		t.Fatalf("expected filter to be cleared, state is %s", model.FilterState())
+	}
+	if got := model.FilterInput.Value(); got != "" {
+		t.Fatalf("expected empty query, got %q", got)
+	}
+	if got := (tableData{m: model}).Rows(); got != 4 {
+		t.Fatalf("expected all 4 rows to be visible, got %d", got)
+	}
+	if model.Cursor() != 3 {
+		t.Fatalf("expected cursor to stay on Bobby at 3, got %d", model.Cursor())
+	}
+
+	t.Run("escape while typing cancels the filter", func(t *testing.T) {
+		model, _ := model.Update(keys("/"))
+		model, _ = model.Update(keys("carol"))
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if model.FilterState() != Unfiltered {
+			t.Fatalf("expected filter to be cleared, state is %s", model.FilterState())
+		}
+		if got := model.SelectedRow(); len(got) == 0 || got[0] != "Carol" {
+			t.Fatalf("expected Carol to stay selected, got %v", got)
+		}
+	})
+}
+
+func TestColumnMinMaxWidths(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "ID", Width: 4},
+			{Title: "Name"},
+			{Title: "Description"},
+			{Title: "Tag", MaxWidth: 2},
+			{Title: "Note"},
+		}),
+		WithRows([]Row{
+			{"1", "Al", "A very long description", "important", "ok"},
+		}),
+		WithColumnMinWidths([]int{-1, 6}),
+		WithColumnMaxWidths([]int{-1, -1, 10, -1, -1, 3}),
+	)
+
+	got := model.getMaxColumnWidths()
+	want := []int{4, 6, 10, 2, 2}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected widths %v, got %v", want, got)
+		}
+	}
+}
+
+func TestEmptyCell(t *testing.T) {
+	rows := []Row{{"1", ""}, {"", "Bob"}}
+	model := New(
+		WithColumns([]Column{{Title: "ID"}, {Title: "Name"}}),
+		WithRows(rows),
+		WithEmptyCell("—"),
+	)
+	td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	tests := []struct {
+		row, col int
+		want     string
+	}{
+		{0, 0, "1"},
+		{0, 1, "—  "},
+		{1, 0, "—"},
+		{1, 1, "Bob"},
+	}
+	for _, tc := range tests {
+		if got := td.At(tc.row, tc.col); got != tc.want {
+			t.Errorf("cell (%d, %d): expected %q, got %q", tc.row, tc.col, tc.want, got)
+		}
+	}
+	if got := model.SelectedRow()[1]; got != "" {
+		t.Fatalf("expected the selected row to keep the empty value, got %q", got)
+	}
+}
+
+func TestColumnCursor(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "A"}, {Title: "B"}, {Title: "C"}}),
+		WithRows([]Row{{"a1", "b1", "c1"}, {"a2", "b2", "c2"}, {"a3", "b3", "c3"}}),
+		WithFocused(true),
+	)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if model.Cursor() != 2 || model.ColCursor() != 1 {
+		t.Fatalf("expected focused cell (2, 1), got (%d, %d)", model.Cursor(), model.ColCursor())
+	}
+	model.MoveUp(1)
+	if model.ColCursor() != 1 {
+		t.Fatalf("expected MoveUp to keep column 1 focused, got %d", model.ColCursor())
+	}
+
+	model.MoveRight(5)
+	if model.ColCursor() != 2 {
+		t.Fatalf("expected column cursor to stop at the last column, got %d", model.ColCursor())
+	}
+
+	t.Run("style func highlights the focused cell", func(t *testing.T) {
+		focused := lipgloss.NewStyle().Reverse(true)
+		var highlighted [][2]int
+		model.SetStyleFunc(func(m Model, row, col int) lipgloss.Style {
+			if row == m.Cursor() && col == m.ColCursor() {
+				highlighted = append(highlighted, [2]int{row, col})
+				return focused
+			}
+			return lipgloss.NewStyle()
+		})
+		model.View()
+		for _, cell := range highlighted {
+			if cell != [2]int{1, 2} {
+				t.Fatalf("expected only cell (1, 2) to be highlighted, got %v", highlighted)
+			}
+		}
+		if len(highlighted) == 0 {
+			t.Fatal("expected the focused cell to be highlighted")
+		}
+	})
+}
+
+func TestRowVisibility(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Value", Width: 10}}),
+		WithRows([]Row{{"a"}, {"b"}, {"c1\nc2\nc3"}, {"d"}}),
+		WithHeight(3),
+	)
+
+	tests := []struct {
+		row              int
+		visible, partial bool
+	}{
+		{0, true, false},
+		{1, true, false},
+		{2, true, true},
+		{3, false, false},
+	}
+	for _, tc := range tests {
+		visible, partial := model.RowVisibility(tc.row)
+		if visible != tc.visible || partial != tc.partial {
+			t.Errorf("row %d: expected (%t, %t), got (%t, %t)",
+				tc.row, tc.visible, tc.partial, visible, partial)
+		}
+	}
+
+	view := ansi.Strip(model.View())
+	if !strings.Contains(view, "c1") || strings.Contains(view, "c2") {
+		t.Fatalf("expected the tall row to be cut after its first line:\n%s", view)
+	}
+
+	model.SetCursor(2)
+	if visible, partial := model.RowVisibility(2); !visible || partial {
+		t.Fatalf("expected the cursor row to be scrolled fully into view, got (%t, %t)", visible, partial)
+	}
+	if visible, _ := model.RowVisibility(0); visible {
+		t.Fatal("expected the first row to be scrolled out of view")
+	}
+}
+
+func TestRowLabelColumn(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "Label", Width: 5},
+			{Title: "Col1", Width: 5},
+			{Title: "Col2", Width: 5},
+			{Title: "Col3", Width: 5},
+		}),
+		WithRows([]Row{{"row1", "a1", "b1", "c1"}, {"row2", "a2", "b2", "c2"}}),
+		// Three columns of 5 characters with a padding of 2, and 2 borders.
+		WithWidth(23),
+		WithHorizontalScroll(true),
+		WithRowLabelColumn(true),
+		WithFocused(true),
+	)
+	header := func() string {
+		return strings.Split(ansi.Strip(model.View()), "\n")[1]
+	}
+
+	if got := header(); !strings.Contains(got, "Label") || !strings.Contains(got, "Col2") || strings.Contains(got, "Col3") {
+		t.Fatalf("expected Label, Col1 and Col2 to be visible, got %q", got)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if model.ColCursor() != 3 {
+		t.Fatalf("expected column cursor on the last column, got %d", model.ColCursor())
+	}
+	got := header()
+	if !strings.Contains(got, "Label") || !strings.Contains(got, "Col3") {
+		t.Fatalf("expected Label to stay and Col3 to scroll into view, got %q", got)
+	}
+	if strings.Contains(got, "Col1") {
+		t.Fatalf("expected Col1 to be scrolled out of view, got %q", got)
+	}
+}
+
+func TestSetKeyMap(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Col1", Width: 10}}),
+		WithRows([]Row{{"First"}, {"Second"}, {"Third"}}),
+		WithFocused(true),
+		WithFiltering(true),
+	)
+
+	km := DefaultKeyMap()
+	km.LineDown = key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "down"))
+	model.SetKeyMap(km)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if model.Cursor() != 0 {
+		t.Fatalf("expected the old binding to do nothing, cursor is %d", model.Cursor())
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if model.Cursor() != 1 {
+		t.Fatalf("expected the new binding to move down, cursor is %d", model.Cursor())
+	}
+	if !strings.Contains(model.HelpView(), "s down") {
+		t.Fatalf("expected help to show the new binding, got %q", model.HelpView())
+	}
+	if model.KeyMap.ClearFilter.Enabled() {
+		t.Fatal("expected clear filter to stay disabled without a filter")
+	}
+}
+
+func TestAccordion(t *testing.T) {
+	rows := []Row{
+		{"fruit", "Apple\nRed and crunchy"},
+		{"fruit", "Banana\nYellow and soft"},
+		{"vegetable", "Carrot\nOrange and long"},
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Group"}, {Title: "Name"}}),
+		WithRows(rows),
+		WithFocused(true),
+		WithExpandableRows(true),
+		WithAccordion(true),
+		WithGroupFunc(func(row int) string { return rows[row][0] }),
+	)
+
+	if got := model.cellValue(0, 1); got != "Apple" {
+		t.Fatalf("expected a collapsed row to show its first line, got %q", got)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	model.Expand(2)
+	if !model.IsExpanded(0) || !model.IsExpanded(2) {
+		t.Fatal("expected rows 0 and 2 to be expanded")
+	}
+	if got := model.cellValue(0, 1); got != "Apple\nRed and crunchy" {
+		t.Fatalf("expected an expanded row to show all its lines, got %q", got)
+	}
+
+	model.Expand(1)
+	if model.IsExpanded(0) {
+		t.Fatal("expected expanding row 1 to collapse row 0 of the same group")
+	}
+	if !model.IsExpanded(1) || !model.IsExpanded(2) {
+		t.Fatal("expected rows 1 and 2 to stay expanded")
+	}
+}
+
+func TestLineNumbers(t *testing.T) {
+	var rows []Row
+	for i := 0; i < 12; i++ {
+		rows = append(rows, Row{fmt.Sprintf("row%d", i)})
+	}
+	gutter := func(model Model) []string {
+		widths := model.getMaxColumnWidths()
+		td := tableData{m: model, maxColumnWidths: widths, columns: model.visibleColumns(widths)}
+		numbers := make([]string, td.Rows())
+		for i := range numbers {
+			numbers[i] = td.At(i, 0)
+		}
+		return numbers
+	}
+
+	tests := []struct {
+		name     string
+		relative bool
+		want     []string
+	}{
+		{"absolute", false, []string{" 9", "10", "11"}},
+		{"relative", true, []string{"1", "2", "3"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			model := New(
+				WithColumns([]Column{{Title: "Name", Width: 10}}),
+				WithRows(rows),
+				WithHeight(3),
+				WithLineNumbers(true),
+				WithRelativeLineNumbers(tc.relative),
+			)
+			model.SetCursor(10)
+			if got := gutter(model); strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("expected line numbers %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHeaderRenderer(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Age", Width: 8}}),
+		WithRows([]Row{{"Alice", "30"}}),
+		WithHeaderRenderer(func(col int, title string, width int) string {
+			if col == 1 {
+				return title + " ▲"
+			}
+			return strings.ToUpper(title)
+		}),
+	)
+
+	header := strings.Split(ansi.Strip(model.View()), "\n")[1]
+	if !strings.Contains(header, "NAME") || !strings.Contains(header, "Age ▲") {
+		t.Fatalf("expected the rendered header titles, got %q", header)
+	}
+
+	widths := model.getMaxColumnWidths()
+	for i, title := range model.getRenderColumns(widths, model.visibleColumns(widths)) {
+		if w := ansi.StringWidth(title); w != widths[i] {
+			t.Fatalf("expected header %d to be %d wide, got %d", i, widths[i], w)
+		}
+	}
+}
+
+func TestTheme(t *testing.T) {
+	theme := MinimalTheme()
+	theme.KeyMap.LineDown = key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "down"))
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}}),
+		WithFocused(true),
+		WithTheme(theme),
+	)
+
+	if !model.styles.Selected.GetReverse() {
+		t.Fatal("expected the theme's selected style to be applied")
+	}
+	if got := model.styleFunc(model, 0, 0); !got.GetReverse() {
+		t.Fatal("expected the theme's styles to be used for the selected row")
+	}
+	if model.border != lipgloss.HiddenBorder() {
+		t.Fatal("expected the theme's border to be applied")
+	}
+	if strings.Contains(model.View(), "│") {
+		t.Fatalf("expected no visible border, got:\n%s", model.View())
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if model.Cursor() != 1 {
+		t.Fatalf("expected the theme's binding to move down, cursor is %d", model.Cursor())
+	}
+
+	t.Run("padding", func(t *testing.T) {
+		theme := DefaultTheme()
+		theme.Padding = &ColumnPadding{Left: 2, Right: 3}
+		model := New(
+			WithColumns([]Column{{Title: "Name", Width: 6}}),
+			WithRows([]Row{{"Alice"}}),
+			WithTheme(theme),
+		)
+		if view := ansi.Strip(model.View()); !strings.Contains(view, "│  Alice    │") {
+			t.Fatalf("expected the theme's padding around the cells, got:\n%s", view)
+		}
+	})
+}
+
+func TestViewWithoutColumns(t *testing.T) {
+	rows := []Row{{"a1", "b1"}, {"a2"}, {"a3", "b3", "c3"}}
+
+	t.Run("rows without columns", func(t *testing.T) {
+		model := New(WithRows(rows), WithFocused(true))
+		view := ansi.Strip(model.View())
+		for _, want := range []string{"a1", "b1", "a2", "c3"} {
+			if !strings.Contains(view, want) {
+				t.Fatalf("expected %q to be rendered, got:\n%s", want, view)
+			}
+		}
+		if got := model.headerHeight(); got != 1 {
+			t.Fatalf("expected no header lines besides the top border, got %d", got)
+		}
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+		model.SetHeight(2)
+		model.View()
+	})
+	t.Run("ragged rows with columns", func(t *testing.T) {
+		model := New(
+			WithColumns([]Column{{Title: "A"}, {Title: "B"}}),
+			WithRows(rows),
+			WithEmptyCell("-"),
+		)
+		widths := model.getMaxColumnWidths()
+		td := tableData{m: model, maxColumnWidths: widths}
+		if got := td.At(1, 1); got != "- " {
+			t.Fatalf("expected a missing cell to render as empty, got %q", got)
+		}
+		model.View()
+	})
+	t.Run("no columns and no rows", func(t *testing.T) {
+		model := New()
+		if got := model.View(); got != "" {
+			t.Fatalf("expected an empty view, got %q", got)
+		}
+	})
+	t.Run("no columns and empty rows", func(t *testing.T) {
+		for _, model := range []Model{
+			New(WithRows([]Row{{}, {}}), WithWidth(10)),
+			New(WithRows([]Row{{}, {}}), WithWidth(10), WithHeight(3), WithLineNumbers(true)),
+		} {
+			if got := model.View(); got != "" {
+				t.Fatalf("expected an empty view, got %q", got)
+			}
+		}
+	})
+}
+
+func TestCollapseDuplicates(t *testing.T) {
+	rows := []Row{
+		{"fruit", "Apple"},
+		{"fruit", "Banana"},
+		{"vegetable", "Carrot"},
+		{"vegetable", "Leek"},
+		{"vegetable", "Onion"},
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Category"}, {Title: "Name"}}),
+		WithRows(rows),
+		WithCollapseDuplicates([]int{0}),
+	)
+
+	want := []string{"fruit", "", "vegetable", "", ""}
+	for i, w := range want {
+		if got := model.cellValue(i, 0); got != w {
+			t.Errorf("row %d: expected category %q, got %q", i, w, got)
+		}
+		if got := model.cellValue(i, 1); got != rows[i][1] {
+			t.Errorf("row %d: expected name %q, got %q", i, rows[i][1], got)
+		}
+	}
+	if got := model.Rows()[1][0]; got != "fruit" {
+		t.Fatalf("expected the stored rows to be intact, got %q", got)
+	}
+
+	model.SetHeight(2)
+	model.SetCursor(4)
+	if got := model.cellValue(3, 0); got != "vegetable" {
+		t.Fatalf("expected the first visible row to show its category, got %q", got)
+	}
+}
+
+func TestJumpToNextInColumn(t *testing.T) {
+	isError := func(v string) bool { return v == "ERROR" }
+	model := New(
+		WithColumns([]Column{{Title: "Job"}, {Title: "Status"}}),
+		WithRows([]Row{
+			{"build", "OK"},
+			{"lint", "ERROR"},
+			{"test", "OK"},
+			{"deploy", "OK"},
+			{"notify", "ERROR"},
+		}),
+		WithHeight(2),
+		WithFocused(true),
+		WithJumpTarget(1, isError),
+	)
+
+	if !model.JumpToNextInColumn(1, isError) || model.Cursor() != 1 {
+		t.Fatalf("expected to jump to the first error at 1, cursor is %d", model.Cursor())
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if model.Cursor() != 4 {
+		t.Fatalf("expected to skip the OK rows and land on 4, cursor is %d", model.Cursor())
+	}
+	if visible, _ := model.RowVisibility(4); !visible {
+		t.Fatal("expected the matching row to be scrolled into view")
+	}
+	if model.JumpToNextInColumn(1, isError) || model.Cursor() != 4 {
+		t.Fatalf("expected no further match and the cursor to stay, cursor is %d", model.Cursor())
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if model.Cursor() != 1 {
+		t.Fatalf("expected to jump back to the previous error at 1, cursor is %d", model.Cursor())
+	}
+}
+
+func TestMargin(t *testing.T) {
+	opts := []Option{
+		WithColumns([]Column{{Title: "Name", Width: 6}}),
+		WithRows([]Row{{"Alice"}}),
+	}
+	plain := ansi.Strip(New(opts...).View())
+	view := ansi.Strip(New(append(opts, WithMargin(1, 2, 3, 4))...).View())
+
+	plainLines := strings.Split(plain, "\n")
+	lines := strings.Split(view, "\n")
+	if len(lines) != len(plainLines)+4 {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(plainLines)+4, len(lines), view)
+	}
+	width := lipgloss.Width(plain) + 6
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w != width {
+			t.Fatalf("line %d: expected width %d, got %d", i, width, w)
+		}
+	}
+	if strings.TrimSpace(lines[0]) != "" || strings.TrimSpace(lines[len(lines)-1]) != "" {
+		t.Fatalf("expected blank lines around the table:\n%s", view)
+	}
+	if got := lines[1]; got != "    "+plainLines[0]+"  " {
+		t.Fatalf("expected 4 columns on the left and 2 on the right, got %q", got)
+	}
+}
+
+func TestCursorBinding(t *testing.T) {
+	cursor := -1
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Carol"}}),
+		WithFocused(true),
+		WithCursorBinding(&cursor),
+	)
+	if cursor != 0 {
+		t.Fatalf("expected the bound cursor to be initialized to 0, got %d", cursor)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if cursor != 1 {
+		t.Fatalf("expected the bound cursor to follow the key press, got %d", cursor)
+	}
+	model.GotoBottom()
+	if cursor != 2 {
+		t.Fatalf("expected the bound cursor to follow GotoBottom, got %d", cursor)
+	}
+	model.SetCursor(0)
+	if cursor != model.Cursor() {
+		t.Fatalf("expected the bound cursor to be %d, got %d", model.Cursor(), cursor)
+	}
+}
+
+func TestBarColumn(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}, {Title: "Load", Width: 10}}),
+		WithRows([]Row{{"full", "100"}, {"half", "50"}, {"over", "250"}, {"none", "-3"}, {"text", "n/a"}}),
+		WithBarColumn(1, 100, lipgloss.NewStyle()),
+	)
+	td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	want := []string{
+		"██████████",
+		"█████     ",
+		"██████████",
+		"          ",
+		"n/a       ",
+	}
+	for i, w := range want {
+		if got := td.At(i, 1); got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+	if got := model.Rows()[1][1]; got != "50" {
+		t.Fatalf("expected the stored value to be intact, got %q", got)
+	}
+}
+
+func TestSparklineColumn(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Trend", Width: 5}}),
+		WithRows([]Row{{"1,3,2,5,4"}, {"2,2"}, {"1,2,3,4,5,6,7,8"}, {"1,x,3"}}),
+		WithSparklineColumn(0),
+	)
+	td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	want := []string{"▁▅▃█▆", "▁▁   ", "▁▃▅▆█", "1,x,3"}
+	for i, w := range want {
+		if got := td.At(i, 0); got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+}
+
+func TestTagColumn(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Tags", Width: 14}}),
+		WithRows([]Row{{"go,rust,zig"}, {"go, zig"}, {""}}),
+		WithTagColumn(0, ",", lipgloss.NewStyle()),
+	)
+	td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	want := []string{" go   rust  +1", " go   zig     ", "              "}
+	for i, w := range want {
+		if got := td.At(i, 0); got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+	if got := model.Rows()[0][0]; got != "go,rust,zig" {
+		t.Fatalf("expected the stored value to be intact, got %q", got)
+	}
+}
+
+func TestRelativeTimeColumn(t *testing.T) {
+	stamp := time.Now().Add(-90 * time.Second).Format(time.RFC3339)
+	model := New(
+		WithColumns([]Column{{Title: "Time", Width: 8}}),
+		WithRows([]Row{{stamp}, {"yesterday"}}),
+		WithRelativeTimeColumn(0, time.RFC3339),
+		WithRelativeTimeRefresh(time.Millisecond),
+	)
+	td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	want := []string{"1m ago  ", "yesterd…"}
+	for i, w := range want {
+		if got := td.At(i, 0); got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+	if got := model.Rows()[0][0]; got != stamp {
+		t.Fatalf("expected the stored value to be intact, got %q", got)
+	}
+
+	_, cmd := model.Update(model.RelativeTimeTick()())
+	if cmd == nil {
+		t.Fatal("expected a refresh to schedule the next one")
+	}
+}
+
+func TestInlineHelp(t *testing.T) {
+	opts := []Option{
+		WithColumns([]Column{{Title: "Name", Width: 20}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}}),
+	}
+	if view := ansi.Strip(New(opts...).View()); strings.Contains(view, "up") {
+		t.Fatalf("expected no help by default, got:\n%s", view)
+	}
+
+	model := New(append(opts, WithInlineHelp(true))...)
+	view := ansi.Strip(model.View())
+	lines := strings.Split(view, "\n")
+	if got := lines[len(lines)-1]; !strings.Contains(got, "↑/k up") || !strings.Contains(got, "↓/j down") {
+		t.Fatalf("expected the short help on the last line, got %q", got)
+	}
+
+	model.Help.ShowAll = true
+	if view := ansi.Strip(model.View()); !strings.Contains(view, "go to start") {
+		t.Fatalf("expected the full help, got:\n%s", view)
+	}
+}
+
+func TestMergeKeyMap(t *testing.T) {
+	base := DefaultKeyMap()
+	km := MergeKeyMap(base, KeyMap{
+		LineDown: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "down")),
+		PageDown: key.NewBinding(key.WithKeys("f"), key.WithDisabled()),
+	})
+
+	if got := km.LineDown.Keys(); len(got) != 1 || got[0] != "s" {
+		t.Fatalf("expected LineDown to be overridden, got %v", got)
+	}
+	if km.PageDown.Enabled() {
+		t.Fatal("expected PageDown to be overridden with a disabled binding")
+	}
+	for name, pair := range map[string][2]key.Binding{
+		"LineUp":     {km.LineUp, base.LineUp},
+		"GotoTop":    {km.GotoTop, base.GotoTop},
+		"GotoBottom": {km.GotoBottom, base.GotoBottom},
+		"Filter":     {km.Filter, base.Filter},
+	} {
+		if strings.Join(pair[0].Keys(), ",") != strings.Join(pair[1].Keys(), ",") {
+			t.Errorf("expected %s to keep its default keys %v, got %v", name, pair[1].Keys(), pair[0].Keys())
+		}
+	}
+}
+
+func TestSetFocused(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 10}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Carol"}}),
+		WithFocused(true),
+		WithFiltering(true),
+	)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("bo")})
+
+	model.SetFocused(false)
+	if model.Focused() {
+		t.Fatal("expected the table to be blurred")
+	}
+	if model.SettingFilter() || model.FilterInput.Focused() {
+		t.Fatal("expected blurring to exit filter editing")
+	}
+	if !model.IsFiltered() {
+		t.Fatalf("expected the typed query to stay applied, state is %s", model.FilterState())
+	}
+
+	model.SetFocused(true)
+	if !model.Focused() {
+		t.Fatal("expected the table to be focused")
+	}
+}
+
+func TestFrozenRows(t *testing.T) {
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(4),
+		WithFrozenRows(2),
+	)
+	model.GotoBottom()
+
+	view := ansi.Strip(model.View())
+	for _, want := range []string{"row 0", "row 1", "row 8", "row 9"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q to be visible in\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "row 7") {
+		t.Errorf("expected row 7 to be scrolled out in\n%s", view)
+	}
+
+	model.GotoTop()
+	if got := model.SelectedRow(); got[0] != "row 0" {
+		t.Fatalf("expected the frozen row 0 to be selectable, got %v", got)
+	}
+	if visible, _ := model.RowVisibility(8); !visible {
+		t.Error("expected the scrolled rows to keep their position when selecting a frozen row")
+	}
+}
+
+func TestFillHeight(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}}),
+		WithHeight(5),
+		WithFillHeight(true),
+	)
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	// Top border, header, header border, 5 data lines and the bottom border.
+	if len(lines) != 9 {
+		t.Fatalf("expected 5 data lines, got %d lines in\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	if got := strings.TrimSpace(strings.Trim(lines[7], "│")); got != "" {
+		t.Fatalf("expected a blank filler line, got %q", lines[7])
+	}
+
+	model.GotoBottom()
+	if model.Cursor() != 1 {
+		t.Fatalf("expected filler rows not to be selectable, cursor is %d", model.Cursor())
+	}
+}
+
+func TestSetActionEnabled(t *testing.T) {
+	rows := make([]Row, 20)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(5),
+		WithFocused(true),
+	)
+	model.SetActionEnabled(ActionPageDown, false)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if model.Cursor() != 0 {
+		t.Fatalf("expected a disabled PageDown to do nothing, cursor is %d", model.Cursor())
+	}
+	model.Help.ShowAll = true
+	if help := model.HelpView(); strings.Contains(help, "f/pgdn") {
+		t.Fatalf("expected PageDown to be hidden from help, got %q", help)
+	}
+
+	model.SetKeyMap(DefaultKeyMap())
+	if model.ActionEnabled(ActionPageDown) {
+		t.Fatal("expected PageDown to stay disabled when replacing the key map")
+	}
+
+	model.SetActionEnabled(ActionPageDown, true)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if model.Cursor() == 0 {
+		t.Fatal("expected PageDown to move the cursor once enabled again")
+	}
+}
+
+func TestColumnType(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "Name", Width: 6},
+			{Title: "Size", Width: 6, Type: NumberColumn},
+			{Title: "Done", Width: 4, Type: BoolColumn},
+		}),
+		WithRows([]Row{
+			{"a", "10", "true"},
+			{"b", "9", "false"},
+			{"c", "100", "maybe"},
+		}),
+	)
+	data := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	if got := data.At(1, 1); got != "     9" {
+		t.Errorf("expected number to be right aligned, got %q", got)
+	}
+	for row, want := range []string{"✓   ", "✗   ", "may…"} {
+		if got := data.At(row, 2); got != want {
+			t.Errorf("expected bool cell %d to render %q, got %q", row, want, got)
+		}
+	}
+
+	model.SortBy(1, false)
+	var got []string
+	for i := 0; i < model.numRows(); i++ {
+		got = append(got, model.rawValue(i, 1))
+	}
+	if strings.Join(got, ",") != "9,10,100" {
+		t.Fatalf("expected numeric sort, got %v", got)
+	}
+	if model.Rows()[0][1] != "10" {
+		t.Fatal("expected the stored rows to keep their order")
+	}
+
+	model.SortBy(1, true)
+	if got := model.SelectedRow(); got[1] != "10" {
+		t.Fatalf("expected the cursor to stay on the selected row, got %v", got)
+	}
+	model.GotoTop()
+	if got := model.SelectedRow(); got[1] != "100" {
+		t.Fatalf("expected the largest value first when descending, got %v", got)
+	}
+}
+
+func TestBoolGlyphs(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "Feature", Width: 7},
+			{Title: "Free", Width: 4, Alignment: lipgloss.Center},
+			{Title: "Pro", Width: 3, Type: BoolColumn},
+		}),
+		WithRows([]Row{
+			{"Export", "true", "yes"},
+			{"Sync", "False", "1"},
+		}),
+		WithBoolGlyphs("☑", "☐"),
+	)
+	data := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	for _, tc := range []struct {
+		row, col int
+		want     string
+	}{
+		{0, 1, " ☑  "},
+		{1, 1, " ☐  "},
+		{0, 2, "yes"},
+		{1, 2, "☑  "},
+		{0, 0, "Export "},
+	} {
+		if got := data.At(tc.row, tc.col); got != tc.want {
+			t.Errorf("cell (%d, %d): expected %q, got %q", tc.row, tc.col, tc.want, got)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	rows := make([]Row, 20)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %02d", i), fmt.Sprint(i % 3), "line\nmore"}
+	}
+	newModel := func() Model {
+		return New(
+			WithColumns([]Column{
+				{Title: "Name", Width: 8},
+				{Title: "Group", Width: 5, Type: NumberColumn},
+				{Title: "Notes", Width: 6},
+			}),
+			WithRows(rows),
+			WithHeight(4),
+			WithFiltering(true),
+			WithExpandableRows(true),
+		)
+	}
+
+	model := newModel()
+	model.SortBy(1, true)
+	model.Focus()
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("/")},
+		{Type: tea.KeyRunes, Runes: []rune("1")},
+		{Type: tea.KeyEnter},
+	} {
+		model, _ = model.Update(msg)
+	}
+	model.Expand(1)
+	model.MoveDown(4)
+	model.MoveRight(2)
+
+	state := model.State()
+	if state.Version != TableStateVersion {
+		t.Fatalf("expected version %d, got %d", TableStateVersion, state.Version)
+	}
+	restored := newModel()
+	restored.SetState(state)
+
+	if got, want := restored.View(), model.View(); got != want {
+		t.Fatalf("expected restored view\n%s\nto equal\n%s", got, want)
+	}
+	if got, want := restored.SelectedRow(), model.SelectedRow(); got[0] != want[0] {
+		t.Fatalf("expected %v to be selected, got %v", want, got)
+	}
+	if restored.ColCursor() != 2 || !restored.IsFiltered() {
+		t.Fatalf("expected the column cursor and filter to be restored")
+	}
+}
+
+func TestRowTransform(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}, {Title: "Level", Width: 5}}),
+		WithRows([]Row{{"disk", "warn"}, {"cpu", "ok"}}),
+		WithRowTransform(func(r Row) Row {
+			if r[1] != "warn" {
+				return r
+			}
+			return Row{strings.ToUpper(r[0]), r[1]}
+		}),
+	)
+	data := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	if got := data.At(0, 0); got != "DISK  " {
+		t.Errorf("expected the transformed value, got %q", got)
+	}
+	if got := data.At(1, 0); got != "cpu   " {
+		t.Errorf("expected the row to be untouched, got %q", got)
+	}
+	if got := model.SelectedRow(); got[0] != "disk" {
+		t.Errorf("expected the selected row to keep its stored value, got %v", got)
+	}
+}
+
+func TestTree(t *testing.T) {
+	depths := []int{0, 1, 2, 1, 0}
+	model := New(
+		WithColumns([]Column{{Title: "Name"}}),
+		WithRows([]Row{{"src"}, {"table"}, {"table.go"}, {"list"}, {"go.mod"}}),
+		WithTree(TreeConfig{Depth: func(i int) int { return depths[i] }}),
+	)
+	names := func() string {
+		var names []string
+		for i := 0; i < model.numRows(); i++ {
+			names = append(names, model.rawValue(i, 0))
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := names(); got != "src,go.mod" {
+		t.Fatalf("expected children to be hidden until expanded, got %s", got)
+	}
+	model.Expand(0)
+	if got := names(); got != "src,table,list,go.mod" {
+		t.Fatalf("expected the children of src to be visible, got %s", got)
+	}
+	model.Expand(1)
+	if got := names(); got != "src,table,table.go,list,go.mod" {
+		t.Fatalf("expected the grandchildren to be visible, got %s", got)
+	}
+
+	model.SetCursor(4)
+	model.Collapse(0)
+	if got := names(); got != "src,go.mod" {
+		t.Fatalf("expected collapsing src to hide its descendants, got %s", got)
+	}
+	if got := model.SelectedRow(); got[0] != "go.mod" {
+		t.Fatalf("expected the cursor to stay on go.mod, got %v", got)
+	}
+
+	model.Expand(0)
+	if got := names(); got != "src,table,table.go,list,go.mod" {
+		t.Fatalf("expected expanding src to show its descendants again, got %s", got)
+	}
+	view := ansi.Strip(model.View())
+	for _, want := range []string{"▾ src", "  ▾ table ", "      table.go", "    list", "  go.mod"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in\n%s", want, view)
+		}
+	}
+
+	t.Run("sorting keeps the children under their parent", func(t *testing.T) {
+		model.SortBy(0, false)
+		if got := names(); got != "go.mod,src,list,table,table.go" {
+			t.Fatalf("expected the siblings to be sorted among themselves, got %s", got)
+		}
+		model.SortBy(0, true)
+		if got := names(); got != "src,table,table.go,list,go.mod" {
+			t.Fatalf("expected the siblings to be sorted in descending order, got %s", got)
+		}
+	})
+}
+
+func TestHelpBindings(t *testing.T) {
+	model := New(WithColumns([]Column{{Title: "Name", Width: 6}}))
+	model.SetActionEnabled(ActionGotoTop, false)
+
+	km := model.HelpBindings()
+	for _, b := range km.ShortHelp() {
+		if !b.Enabled() {
+			t.Errorf("expected only enabled bindings in the short help, got %q", b.Help().Desc)
+		}
+	}
+	var descs []string
+	for _, group := range km.FullHelp() {
+		if len(group) == 0 {
+			t.Error("expected empty groups to be left out")
+		}
+		for _, b := range group {
+			descs = append(descs, b.Help().Desc)
+		}
+	}
+	got := strings.Join(descs, ",")
+	for _, disabled := range []string{"go to start", "filter", "expand"} {
+		if strings.Contains(got, disabled) {
+			t.Errorf("expected %q to be left out, got %s", disabled, got)
+		}
+	}
+	if !strings.Contains(got, "go to end") {
+		t.Errorf("expected enabled bindings to be kept, got %s", got)
+	}
+
+	t.Run("filter keys in the full help only", func(t *testing.T) {
+		model := New(WithColumns([]Column{{Title: "Name", Width: 6}}), WithFiltering(true))
+		model.Help.ShowAll = false
+		if help := model.HelpView(); strings.Contains(help, "filter") {
+			t.Fatalf("expected the filter keys to be left out of the short help, got %q", help)
+		}
+		model.Help.ShowAll = true
+		if help := model.HelpView(); !strings.Contains(help, "filter") {
+			t.Fatalf("expected the filter keys in the full help, got %q", help)
+		}
+	})
+}
+
+func TestColumnPadding(t *testing.T) {
+	newModel := func(opts ...Option) Model {
+		return New(append([]Option{
+			WithColumns([]Column{{Title: "A", Width: 3}, {Title: "B", Width: 3}}),
+			WithRows([]Row{{"foo", "bar"}}),
+		}, opts...)...)
+	}
+	width := func(m Model) int {
+		return lipgloss.Width(m.View())
+	}
+
+	base := width(newModel())
+	padded := newModel(WithColumnPadding(2, 3))
+	// The default styles pad each column by 1 on both sides.
+	if got, want := width(padded), base+2*3; got != want {
+		t.Fatalf("expected width %d, got %d", want, got)
+	}
+	if got := ansi.Strip(padded.View()); !strings.Contains(got, "│  foo   │  bar   │") {
+		t.Fatalf("expected padded cells in\n%s", got)
+	}
+
+	padded.SetColumnPadding(1, 0, 0)
+	if got, want := width(padded), base+3-2; got != want {
+		t.Fatalf("expected width %d with the column override, got %d", want, got)
+	}
+	if got := ansi.Strip(padded.View()); !strings.Contains(got, "│  foo   │bar│") {
+		t.Fatalf("expected the second column without padding in\n%s", got)
+	}
+}
+
+func TestDiffHighlight(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Host", Width: 6}, {Title: "CPU", Width: 4}}),
+		WithRows([]Row{{"web", "10"}, {"db", "20"}}),
+		WithRowKey(func(r Row) string { return r[0] }),
+		WithDiffHighlight(true),
+	)
+	model.SetRows([]Row{{"db", "25"}, {"web", "10"}, {"cache", "5"}})
+
+	changed := func(row, col int) bool {
+		return model.changed[model.rowIndex(row)][col]
+	}
+	for _, tc := range []struct {
+		row, col int
+		want     bool
+	}{
+		{0, 0, false},
+		{0, 1, true},
+		{1, 0, false},
+		{1, 1, false},
+		{2, 0, true},
+		{2, 1, true},
+	} {
+		if got := changed(tc.row, tc.col); got != tc.want {
+			t.Errorf("cell (%d, %d): expected changed to be %t", tc.row, tc.col, tc.want)
+		}
+	}
+	if got, want := model.columnStyle(0, 1).GetForeground(), model.styles.Changed.GetForeground(); got != want {
+		t.Errorf("expected the changed cell to render with the changed style, got %v", got)
+	}
+
+	model.SetRows(model.Rows())
+	if changed(0, 1) {
+		t.Error("expected the highlight to be cleared by the next SetRows")
+	}
+}
+
+func TestRenderLimit(t *testing.T) {
+	rows := []Row{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 4}}),
+		WithRows(rows),
+		WithHeight(100000),
+		WithFillHeight(true),
+		WithRenderLimit(8),
+	)
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	// Top border, header, header border, 8 rows and the bottom border.
+	if len(lines) != 12 {
+		t.Fatalf("expected 5 rows and 3 filler rows, got %d lines", len(lines))
+	}
+	for i, row := range rows {
+		if !strings.Contains(lines[3+i], row[0]) {
+			t.Errorf("expected row %q on line %d, got %q", row[0], 3+i, lines[3+i])
+		}
+	}
+
+	WithRenderLimit(3)(&model)
+	model.GotoBottom()
+	if visible, _ := model.RowVisibility(4); !visible {
+		t.Error("expected the cursor row to stay within the render limit")
+	}
+	if visible, _ := model.RowVisibility(1); visible {
+		t.Error("expected rows beyond the render limit to be scrolled out")
+	}
+}
+
+func TestSmoothScroll(t *testing.T) {
+	rows := make([]Row, 50)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(10),
+		WithFocused(true),
+		WithSmoothScroll(true),
+	)
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if cmd != nil || model.start != 1 {
+		t.Fatalf("expected scrolling by a single row not to be animated, start is %d", model.start)
+	}
+	model, cmd = model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if model.Cursor() != 20 {
+		t.Fatalf("expected the cursor to move at once, got %d", model.Cursor())
+	}
+	starts := []int{model.start}
+	for cmd != nil {
+		model, cmd = model.Update(cmd())
+		starts = append(starts, model.start)
+	}
+	if got, want := fmt.Sprint(starts), "[1 4 7 10 11]"; got != want {
+		t.Fatalf("expected intermediate start positions %s, got %s", want, got)
+	}
+
+	t.Run("navigating again retargets the scroll", func(t *testing.T) {
+		model, stale := model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+		model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+		if model, next := model.Update(stale()); next != nil || model.start != 11 {
+			t.Fatalf("expected the stale frame to be ignored, start is %d", model.start)
+		}
+		for cmd != nil {
+			model, cmd = model.Update(cmd())
+		}
+		if model.start != 31 {
+			t.Fatalf("expected to scroll to the new target, start is %d", model.start)
+		}
+	})
+
+	t.Run("other messages do not cancel the scroll", func(t *testing.T) {
+		model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnd})
+		model, cmd = model.Update(cmd())
+		model, next := model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+		if next != nil {
+			t.Fatalf("expected no command for an unrelated message")
+		}
+		for cmd != nil {
+			model, cmd = model.Update(cmd())
+		}
+		if visible, _ := model.RowVisibility(model.Cursor()); !visible {
+			t.Fatalf("expected the cursor to be scrolled into view, start is %d", model.start)
+		}
+	})
+}
+
+func TestSetFilterQuery(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 10}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Carol"}, {"Bobby"}}),
+		WithFiltering(true),
+	)
+
+	model.SetFilterQuery("BOB")
+	if got := model.FilterQuery(); got != "BOB" {
+		t.Fatalf("expected query %q, got %q", "BOB", got)
+	}
+	if !model.IsFiltered() || model.numRows() != 2 {
+		t.Fatalf("expected 2 matching rows, got %d", model.numRows())
+	}
+	if got := model.SelectedRow(); got[0] != "Bob" {
+		t.Fatalf("expected Bob to be selected, got %v", got)
+	}
+
+	model.SetFilterQuery("")
+	if model.FilterState() != Unfiltered || model.numRows() != 4 {
+		t.Fatalf("expected all rows after clearing the query, got %d rows", model.numRows())
+	}
+	if got := model.FilterQuery(); got != "" {
+		t.Fatalf("expected an empty query, got %q", got)
+	}
+}
+
+func TestFilterColumns(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Team", Width: 8}}),
+		WithRows([]Row{{"Ana", "Core"}, {"Cora", "Docs"}, {"Ben", "Core"}}),
+		WithFiltering(true),
+		FilterColumns(1),
+	)
+
+	model.SetFilterQuery("cor")
+	var names []string
+	for i := 0; i < model.numRows(); i++ {
+		names = append(names, model.rawValue(i, 0))
+	}
+	if got := strings.Join(names, ","); got != "Ana,Ben" {
+		t.Fatalf("expected matches in the Team column only, got %s", got)
+	}
+
+	t.Run("custom matcher", func(t *testing.T) {
+		model := New(
+			WithColumns([]Column{{Title: "Name", Width: 8}}),
+			WithRows([]Row{{"Ana"}, {"ana"}}),
+			WithFilterMatcher(func(row Row, query string) bool {
+				return strings.Contains(row[0], query)
+			}),
+		)
+		model.SetFilterQuery("ana")
+		if model.numRows() != 1 || model.rawValue(0, 0) != "ana" {
+			t.Fatalf("expected the case-sensitive matcher to match a single row, got %d", model.numRows())
+		}
+	})
+}
+
+func TestColumnStripes(t *testing.T) {
+	s := DefaultStyles()
+	s.ColOdd = lipgloss.NewStyle().Background(lipgloss.Color("1"))
+	model := New(
+		WithColumns([]Column{
+			{Title: "ID", Width: 2},
+			{Title: "A", Width: 4},
+			{Title: "B", Width: 4},
+			{Title: "C", Width: 4},
+		}),
+		WithRows([]Row{{"1", "a", "b", "c"}, {"2", "a", "b", "c"}}),
+		WithStyles(s),
+		WithColumnStripes(true),
+		WithLineNumbers(true),
+		WithRowLabelColumn(true),
+		WithHorizontalScroll(true),
+		WithWidth(17),
+	)
+	model.SetColCursor(3)
+
+	columns := model.visibleColumns(model.getMaxColumnWidths())
+	if got := fmt.Sprint(columns); got != "[-1 0 3]" {
+		t.Fatalf("expected the line numbers, the row label and C to be visible, got %s", got)
+	}
+	odd := lipgloss.Color("1")
+	for col, want := range []bool{false, false, true} {
+		got := model.renderedStyle(1, col, columns, model.renderPlan()).GetBackground() == odd
+		if got != want {
+			t.Errorf("rendered column %d: expected striped to be %t", col, want)
+		}
+	}
+	if model.renderedStyle(-1 /* header */, 2, columns, nil).GetBackground() == odd {
+		t.Error("expected the header not to be striped")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}, {Title: "Age", Width: -1}}),
+		WithRows([]Row{{"Ana", "31"}, {"Ben"}, {"Cy", "40", "extra"}}),
+	)
+
+	err := model.Validate()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{
+		`column 1 ("Age") has a negative width: -1`,
+		"row 1 has 1 values, want one per column: 2",
+		"row 2 has 3 values, want one per column: 2",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in the error, got:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "row 0") {
+		t.Errorf("expected row 0 to be valid, got:\n%v", err)
+	}
+
+	model.SetColumns([]Column{{Title: "Name", Width: 6}, {Title: "Age", Width: 3}})
+	model.SetRows([]Row{{"Ana", "31"}})
+	if err := model.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestInsertSeparator(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 12}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Carol"}}),
+		WithFocused(true),
+	)
+	model.InsertSeparator(1, "Others")
+
+	view := ansi.Strip(model.View())
+	if !strings.Contains(view, "│ ── Others ── │") {
+		t.Fatalf("expected a labelled separator in\n%s", view)
+	}
+	if lines := strings.Split(view, "\n"); !strings.Contains(lines[4], "Others") {
+		t.Fatalf("expected the separator between Alice and Bob in\n%s", view)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := model.SelectedRow(); got[0] != "Bob" {
+		t.Fatalf("expected moving down to skip the separator and land on Bob, got %v", got)
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := model.SelectedRow(); got[0] != "Alice" {
+		t.Fatalf("expected moving up to land on Alice, got %v", got)
+	}
+
+	t.Run("separators take a line of the viewport", func(t *testing.T) {
+		model := model
+		model.SetHeight(2)
+		model.GotoBottom()
+		if visible, _ := model.RowVisibility(1); visible {
+			t.Fatal("expected Bob to be scrolled out by the separator")
+		}
+		model.MoveUp(1)
+		if visible, partial := model.RowVisibility(1); !visible || partial {
+			t.Fatal("expected Bob to be fully visible below its separator")
+		}
+	})
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "Name", Width: 10},
+			{Title: "Size", Width: 4, Type: NumberColumn},
+			{Title: "Note", Width: 6, Alignment: lipgloss.Center},
+		}),
+		WithRows([]Row{
+			{"a|b", "10", "one\ntwo"},
+			{"\x1b[1mbold\x1b[0m", "2", ""},
+		}),
+	)
+
+	var b strings.Builder
+	if err := model.WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `| Name | Size | Note |
+| --- | ---: | :---: |
+| a\|b | 10 | one<br>two |
+| bold | 2 |  |
+`
+	if got := b.String(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "Name", Width: 10},
+			{Title: "Size", Width: 4, Type: NumberColumn},
+		}),
+		WithRows([]Row{
+			{"<b>&co</b>", "10"},
+			{"line\nbreak", "2"},
+		}),
+	)
+
+	var b strings.Builder
+	if err := model.WriteHTML(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `<table>
+  <thead>
+    <tr>
+      <th>Name</th>
+      <th style="text-align: right">Size</th>
+    </tr>
+  </thead>
+  <tbody>
+    <tr>
+      <td>&lt;b&gt;&amp;co&lt;/b&gt;</td>
+      <td style="text-align: right">10</td>
+    </tr>
+    <tr>
+      <td>line<br>break</td>
+      <td style="text-align: right">2</td>
+    </tr>
+  </tbody>
+</table>
+`
+	if got := b.String(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestCellClick(t *testing.T) {
+	type click struct{ row, col int }
+	var header []int
+	var cells []click
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}, {Title: "Age", Width: 3}}),
+		WithRows([]Row{{"Alice", "31"}, {"Bob", "40"}}),
+		WithFocused(true),
+		WithMargin(1, 2),
+		WithOrigin(10, 5),
+		WithHeaderClick(func(col int) tea.Cmd {
+			header = append(header, col)
+			return nil
+		}),
+		WithCellClick(func(row, col int) tea.Cmd {
+			cells = append(cells, click{row, col})
+			return nil
+		}),
+	)
+	press := func(x, y int) {
+		// The table is rendered at (10, 5) and inset by the margin.
+		model, _ = model.Update(tea.MouseMsg{
+			X: 10 + 2 + x, Y: 5 + 1 + y,
+			Action: tea.MouseActionPress, Button: tea.MouseButtonLeft,
+		})
+	}
+
+	// ╭────────┬─────╮
+	// │ Name   │ Age │
+	// ├────────┼─────┤
+	// │ Alice  │ 31  │
+	// │ Bob    │ 40  │
+	press(12, 1)
+	press(3, 1)
+	if got := fmt.Sprint(header); got != "[1 0]" {
+		t.Fatalf("expected header clicks on columns 1 and 0, got %s", got)
+	}
+
+	press(11, 4)
+	if got := fmt.Sprint(cells); got != "[{1 1}]" {
+		t.Fatalf("expected a click on row 1, column 1, got %s", got)
+	}
+	if model.Cursor() != 1 || model.ColCursor() != 1 {
+		t.Fatalf("expected the cursor to move to the clicked cell, got (%d, %d)", model.Cursor(), model.ColCursor())
+	}
+
+	press(9, 3)  // column border
+	press(3, 2)  // header border
+	press(3, 10) // below the table
+	if len(cells) != 1 || len(header) != 2 {
+		t.Fatalf("expected clicks outside the cells to be ignored, got %v and %v", cells, header)
+	}
+}
+
+func TestClickToSort(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Age", Width: 5, Type: NumberColumn}}),
+		WithRows([]Row{{"Bob", "40"}, {"Alice", "9"}, {"Cy", "31"}}),
+		WithFocused(true),
+		WithClickToSort(true),
+	)
+	clickHeader := func(x int) {
+		model, _ = model.Update(tea.MouseMsg{X: x, Y: 1, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	}
+	column := func(col int) string {
+		var values []string
+		for i := 0; i < model.numRows(); i++ {
+			values = append(values, model.rawValue(i, col))
+		}
+		return strings.Join(values, ",")
+	}
+
+	// ╭──────────┬───────╮
+	// │ Name     │   Age │
+	clickHeader(14)
+	if got := column(1); got != "9,31,40" {
+		t.Fatalf("expected an ascending sort by age, got %s", got)
+	}
+	if !strings.Contains(ansi.Strip(model.View()), "Age ▲") {
+		t.Fatalf("expected a sort indicator in\n%s", ansi.Strip(model.View()))
+	}
+	clickHeader(14)
+	if got := column(1); got != "40,31,9" {
+		t.Fatalf("expected the second click to reverse the sort, got %s", got)
+	}
+	clickHeader(3)
+	if got := column(0); got != "Alice,Bob,Cy" {
+		t.Fatalf("expected a click on another header to sort by it ascending, got %s", got)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := column(0); got != "Cy,Bob,Alice" {
+		t.Fatalf("expected enter to reverse the sort of the focused column, got %s", got)
+	}
+}
+
+func TestScrollTo(t *testing.T) {
+	rows := make([]Row, 30)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(10),
+	)
+
+	model.ScrollTo(20, 2)
+	if model.Cursor() != 20 {
+		t.Fatalf("expected the cursor on row 20, got %d", model.Cursor())
+	}
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	// Top border, header and header border come first.
+	if got := strings.TrimSpace(strings.Trim(lines[5], "│")); got != "row 20" {
+		t.Fatalf("expected row 20 on the third visible line, got %q in\n%s", got, strings.Join(lines, "\n"))
+	}
+
+	model.ScrollTo(28, 0)
+	if visible, partial := model.RowVisibility(29); !visible || partial {
+		t.Fatal("expected the last row to be fully visible")
+	}
+	if want := 30 - model.viewportRows(); model.start != want {
+		t.Fatalf("expected the viewport to be clamped to start at row %d, got %d", want, model.start)
+	}
+	if model.Cursor() != 28 {
+		t.Fatalf("expected the cursor on row 28, got %d", model.Cursor())
+	}
+}
+
+func TestRowCount(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Alina"}}),
+		WithFiltering(true),
+	)
+	if got := model.RowCount(); got != 3 {
+		t.Fatalf("expected 3 rows, got %d", got)
+	}
+	model.SetFilterQuery("ali")
+	if got := model.RowCount(); got != 2 {
+		t.Fatalf("expected 2 rows matching the filter, got %d", got)
+	}
+	model.ResetFilter()
+	if got := model.RowCount(); got != 3 {
+		t.Fatalf("expected 3 rows after resetting the filter, got %d", got)
+	}
+}
+
+func TestColumnWrap(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}, {Title: "Description", Width: 10, Wrap: true}}),
+		WithRows([]Row{{"Alexandra", "quite long text"}, {"Bob", "short"}}),
+	)
+
+	if got := model.rowHeight(0); got != 2 {
+		t.Fatalf("expected the wrapped row to be 2 lines tall, got %d", got)
+	}
+	if got := model.rowHeight(1); got != 1 {
+		t.Fatalf("expected the short row to be 1 line tall, got %d", got)
+	}
+	td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+	if got, want := td.At(0, 1), "quite long\ntext      "; got != want {
+		t.Fatalf("expected the description to wrap as %q, got %q", want, got)
+	}
+	if got, want := td.At(0, 0), "Alexa…"; got != want {
+		t.Fatalf("expected the name to be truncated as %q, got %q", want, got)
+	}
+}
+
+func TestHeightExcludesHeader(t *testing.T) {
+	rows := make([]Row, 30)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(10),
+		WithVerticalHeaders(true),
+	)
+	model.GotoBottom()
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	if got := model.headerHeight(); got != 6 {
+		t.Fatalf("expected the header to take 6 lines with its borders, got %d", got)
+	}
+	if got, want := len(lines), model.headerHeight()+10+1; got != want {
+		t.Fatalf("expected %d lines, got %d:\n%s", want, got, strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[1], "N") || !strings.Contains(lines[4], "e") {
+		t.Fatalf("expected the header to stay rendered, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if got := strings.TrimSpace(strings.Trim(lines[model.headerHeight()], "│")); got != "row 20" {
+		t.Fatalf("expected row 20 right below the header, got %q", got)
+	}
+}
+
+func TestScrollIndicator(t *testing.T) {
+	rows := make([]Row, 30)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(5),
+		WithScrollIndicator(true),
+	)
+	indicator := func() string {
+		lines := strings.Split(ansi.Strip(model.View()), "\n")
+		return strings.TrimSpace(lines[len(lines)-1])
+	}
+
+	if got := indicator(); got != "↑ 0  ↓ 25" {
+		t.Fatalf("expected the rows below the viewport, got %q", got)
+	}
+	model.SetCursor(10)
+	if got := indicator(); got != "↑ 6  ↓ 19" {
+		t.Fatalf("expected the rows above and below the viewport, got %q", got)
+	}
+	model.GotoBottom()
+	if got := indicator(); got != "↑ 25  ↓ 0" {
+		t.Fatalf("expected the rows above the viewport, got %q", got)
+	}
+
+	model.SetRows(rows[:3])
+	if got := ansi.Strip(model.View()); strings.Contains(got, "↑") {
+		t.Fatalf("expected no indicator when the rows fit, got:\n%s", got)
+	}
+}
+
+func TestWidthFill(t *testing.T) {
+	cellWidths := func(opts ...Option) []int {
+		model := New(append([]Option{
+			WithColumns([]Column{{Title: "A", Width: 4}, {Title: "B", Width: 4}, {Title: "C", Width: 8}}),
+			WithRows([]Row{{"a", "b", "c"}}),
+			WithWidth(30),
+		}, opts...)...)
+		header := strings.Split(ansi.Strip(model.View()), "\n")[1]
+		var widths []int
+		for _, cell := range strings.Split(strings.Trim(header, "│"), "│") {
+			widths = append(widths, ansi.StringWidth(cell))
+		}
+		return widths
+	}
+
+	// The columns take 4+2, 4+2 and 8+2 cells and 2 borders: 6 cells of slack.
+	tests := map[string]struct {
+		mode FillMode
+		want []int
+	}{
+		"right":        {FillRight, []int{6, 6, 16}},
+		"left":         {FillLeft, []int{12, 6, 10}},
+		"column":       {FillColumn(1), []int{6, 12, 10}},
+		"proportional": {FillProportional, []int{7, 7, 14}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := cellWidths(WithWidthFill(tc.mode)); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Fatalf("expected cell widths %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSortState(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Age", Width: 5}}),
+		WithRows([]Row{{"Bob", "40"}, {"Alice", "9"}}),
+	)
+	if _, _, ok := model.SortState(); ok {
+		t.Fatal("expected the rows not to be sorted")
+	}
+
+	model.SortBy(1, true)
+	if col, desc, ok := model.SortState(); !ok || col != 1 || !desc {
+		t.Fatalf("expected a descending sort by column 1, got %d %t %t", col, desc, ok)
+	}
+	model.SetRows([]Row{{"Cy", "31"}})
+	if col, _, ok := model.SortState(); !ok || col != 1 {
+		t.Fatal("expected the sort to be kept when the rows are replaced")
+	}
+
+	model.SetColumns([]Column{{Title: "Name", Width: 8}})
+	if _, _, ok := model.SortState(); ok {
+		t.Fatal("expected the sort to be reset when its column is removed")
+	}
+
+	model.SortBy(0, false)
+	model.ClearSort()
+	if _, _, ok := model.SortState(); ok {
+		t.Fatal("expected the sort to be reset by ClearSort")
+	}
+}
+
+func TestRowTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("row").Parse("{{.Name}} is {{.Age}} years old"))
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Age", Width: 5}}),
+		WithRows([]Row{{"Alice", "30"}, {"Bob", "4"}}),
+		WithRowTemplate(tmpl, []string{"Name", "Age"}),
+		WithStyles(Styles{}),
+	)
+
+	want := "Alice is 30 years old\nBob is 4 years old   "
+	if got := model.View(); got != want {
+		t.Fatalf("expected\n%q\ngot\n%q", want, got)
+	}
+
+	model = New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows([]Row{{"Alice"}}),
+		WithRowTemplate(tmpl, []string{"Name", "Age"}),
+		WithStyles(Styles{}),
+		WithWidth(12),
+	)
+	if got := model.View(); got != "Alice is  y…" {
+		t.Fatalf("expected the line to be truncated to the width, got %q", got)
+	}
+}
+
+func TestFilterDebounce(t *testing.T) {
+	var queries []string
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Alina"}}),
+		WithFiltering(true),
+		WithFilterDebounce(time.Millisecond),
+		WithFilterMatcher(func(row Row, query string) bool {
+			if row[0] == "Alice" {
+				queries = append(queries, query)
+			}
+			return strings.HasPrefix(row[0], query)
+		}),
+	)
+
+	first := model.SetFilterQuery("A")
+	second := model.SetFilterQuery("Ali")
+	if model.numRows() != 3 {
+		t.Fatalf("expected the rows not to be filtered before the debounce, got %d rows", model.numRows())
+	}
+	model, _ = model.Update(first())
+	model, _ = model.Update(second())
+	if fmt.Sprint(queries) != "[Ali]" {
+		t.Fatalf("expected a single filtering with the last query, got %v", queries)
+	}
+	if model.numRows() != 2 {
+		t.Fatalf("expected 2 rows matching the filter, got %d", model.numRows())
+	}
+}
+
+func TestEnsureColumnVisible(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "Label", Width: 5},
+			{Title: "Col1", Width: 5},
+			{Title: "Col2", Width: 5},
+			{Title: "Col3", Width: 5},
+			{Title: "Col4", Width: 5},
+		}),
+		WithRows([]Row{{"row1", "a1", "b1", "c1", "d1"}}),
+		// Three columns of 5 characters with a padding of 2, and 2 borders.
+		WithWidth(23),
+		WithHorizontalScroll(true),
+		WithRowLabelColumn(true),
+	)
+	header := func() string {
+		return strings.Split(ansi.Strip(model.View()), "\n")[1]
+	}
+
+	model.EnsureColumnVisible(4)
+	got := header()
+	if !strings.Contains(got, "Label") || !strings.Contains(got, "Col3") || !strings.Contains(got, "Col4") {
+		t.Fatalf("expected Label, Col3 and Col4 to be visible, got %q", got)
+	}
+	if model.ColCursor() != 0 {
+		t.Fatalf("expected the frozen column to stay focused, got %d", model.ColCursor())
+	}
+
+	model.EnsureColumnVisible(1)
+	if got := header(); !strings.Contains(got, "Col1") || !strings.Contains(got, "Col2") || strings.Contains(got, "Col3") {
+		t.Fatalf("expected Col1 and Col2 to be visible, got %q", got)
+	}
+
+	model.SetColCursor(2)
+	model.EnsureColumnVisible(4)
+	if model.ColCursor() != 3 {
+		t.Fatalf("expected the focus to move to the closest visible column, got %d", model.ColCursor())
+	}
+}
+
+func TestCellSelection(t *testing.T) {
+	styles := Styles{Selected: lipgloss.NewStyle().Bold(true)}
+	model := New(
+		WithColumns([]Column{{Title: "A", Width: 4}, {Title: "B", Width: 4}, {Title: "C", Width: 4}}),
+		WithRows([]Row{{"a1", "b1", "c1"}, {"a2", "b2", "c2"}}),
+		WithStyles(styles),
+		WithCellSelection(true),
+	)
+	model.SetColCursor(1)
+
+	var selected []int
+	for col := 0; col < 3; col++ {
+		if model.columnStyle(0, col).GetBold() {
+			selected = append(selected, col)
+		}
+	}
+	if fmt.Sprint(selected) != "[1]" {
+		t.Fatalf("expected only the focused cell to be selected, got columns %v", selected)
+	}
+	if model.columnStyle(0, 0).GetFaint() {
+		t.Fatal("expected the rest of the row not to be faint by default")
+	}
+
+	WithDimSelectedRow(true)(&model)
+	if !model.columnStyle(0, 0).GetFaint() || model.columnStyle(1, 0).GetFaint() {
+		t.Fatal("expected only the other cells of the selected row to be faint")
+	}
+}
+
+func TestAppendRows(t *testing.T) {
+	rows := func(from, n int) []Row {
+		rows := make([]Row, n)
+		for i := range rows {
+			rows[i] = Row{fmt.Sprintf("row %d", from+i)}
+		}
+		return rows
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 10}}),
+		WithRows(rows(0, 5)),
+		WithHeight(3),
+	)
+	model.SetCursor(1)
+
+	model.AppendRows(rows(5, 1000))
+	if len(model.Rows()) != 1005 {
+		t.Fatalf("expected 1005 rows, got %d", len(model.Rows()))
+	}
+	if model.Cursor() != 1 || model.start > model.Cursor() {
+		t.Fatalf("expected the cursor to stay on row 1 in view, got cursor %d and start %d", model.Cursor(), model.start)
+	}
+
+	WithFollow(true)(&model)
+	model.AppendRows(rows(1005, 10))
+	if model.Cursor() != 1 {
+		t.Fatalf("expected the cursor not to follow from row 1, got %d", model.Cursor())
+	}
+	model.GotoBottom()
+	model.AppendRows(rows(1015, 1000))
+	if model.Cursor() != 2014 {
+		t.Fatalf("expected the cursor to follow to the last row, got %d", model.Cursor())
+	}
+	if visible, partial := model.RowVisibility(2014); !visible || partial {
+		t.Fatalf("expected the last row to be in view, start is %d", model.start)
+	}
+}
+
+func TestSelectionIndicator(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Cy"}}),
+		WithSelectionIndicator(">"),
+		WithLineNumbers(true),
+	)
+	model.SetCursor(1)
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	want := []string{
+		"│  │ 1 │ Alice  │",
+		"│ >│ 2 │ Bob    │",
+		"│  │ 3 │ Cy     │",
+	}
+	for i, w := range want {
+		if got := lines[3+i]; got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+}
+
+func TestDiffRows(t *testing.T) {
+	old := []Row{{"1", "Alice", "30"}, {"2", "Bob", "4"}, {"3", "Cy", "31"}}
+	updated := []Row{{"3", "Cy", "32"}, {"1", "Alice", "30"}, {"4", "Dee", "20"}}
+
+	added, removed, changed := DiffRows(old, updated, func(r Row) string { return r[0] })
+	if fmt.Sprint(added, removed, changed) != "[2] [1] [0]" {
+		t.Fatalf("expected added [2], removed [1] and changed [0], got %v %v %v", added, removed, changed)
+	}
+
+	added, removed, changed = DiffRows(old[:2], updated, nil)
+	if fmt.Sprint(added, removed, changed) != "[2] [] [0 1]" {
+		t.Fatalf("expected added [2], removed [] and changed [0 1] by index, got %v %v %v", added, removed, changed)
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	rows := make([]Row, 30)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(10),
+		WithPageSize(5),
+		WithFocused(true),
+	)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if model.Cursor() != 5 {
+		t.Fatalf("expected a page down to move the cursor by 5, got %d", model.Cursor())
+	}
+	if got := strings.Count(ansi.Strip(model.View()), "row "); got != 10 {
+		t.Fatalf("expected 10 rendered rows, got %d", got)
+	}
+
+	WithPageSize(0)(&model)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if model.Cursor() != 15 {
+		t.Fatalf("expected a page down to move the cursor by the height, got %d", model.Cursor())
+	}
+}
+
+func TestVerticalHeaders(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Alpha", Width: 2}, {Title: "Be", Width: 2, Alignment: lipgloss.Right}}),
+		WithRows([]Row{{"1", "2"}, {"3", "4"}}),
+		WithVerticalHeaders(true),
+		WithHeight(1),
+	)
+
+	want := strings.Join([]string{
+		"╭────┬────╮",
+		"│ A  │  B │",
+		"│ l  │  e │",
+		"│ p  │    │",
+		"│ h  │    │",
+		"│ a  │    │",
+		"├────┼────┤",
+		"│ 1  │  2 │",
+		"╰────┴────╯",
+	}, "\n")
+	if got := ansi.Strip(model.View()); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+	if model.headerHeight() != 7 {
+		t.Fatalf("expected a header 7 lines tall, got %d", model.headerHeight())
+	}
+}
+
+func TestHeaderAlignment(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8, HeaderAlignment: lipgloss.Right}}),
+		WithRows([]Row{{"Alice"}}),
+	)
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	if got, want := lines[1], "│     Name │"; got != want {
+		t.Fatalf("expected a right-aligned header %q, got %q", want, got)
+	}
+	if got, want := lines[3], "│ Alice    │"; got != want {
+		t.Fatalf("expected left-aligned data %q, got %q", want, got)
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 4}, {Title: "Description", Width: 6}}),
+		WithRows([]Row{{"Al", "first"}}),
+		WithSkeleton(2),
+	)
+	model.SetLoading(true)
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	want := []string{
+		"╭──────┬────────╮",
+		"│ Name │ Descr… │",
+		"├──────┼────────┤",
+		"│ ░░░░ │ ░░░░░░ │",
+		"│ ░░░░ │ ░░░░░░ │",
+		"╰──────┴────────╯",
+	}
+	if got := strings.Join(lines, "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("expected\n%s\ngot\n%s", strings.Join(want, "\n"), got)
+	}
+
+	model.SetLoading(false)
+	if view := ansi.Strip(model.View()); !strings.Contains(view, "Al") || strings.Contains(view, "░") {
+		t.Fatalf("expected the rows once loaded, got\n%s", view)
+	}
+}
+
+func TestChangeMessages(t *testing.T) {
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(3),
+		WithFocused(true),
+		WithChangeMessages(true),
+	)
+	messages := func(cmd tea.Cmd) []tea.Msg {
+		if cmd == nil {
+			return nil
+		}
+		msg := cmd()
+		batch, ok := msg.(tea.BatchMsg)
+		if !ok {
+			return []tea.Msg{msg}
+		}
+		var msgs []tea.Msg
+		for _, cmd := range batch {
+			if cmd != nil {
+				msgs = append(msgs, cmd())
+			}
+		}
+		return msgs
+	}
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := fmt.Sprint(messages(cmd)); got != "[{1 [row 1]}]" {
+		t.Fatalf("expected only a selection change within the page, got %s", got)
+	}
+
+	model.SetCursor(2)
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	msgs := messages(cmd)
+	if len(msgs) != 2 {
+		t.Fatalf("expected a selection and a viewport change, got %v", msgs)
+	}
+	if msg, ok := msgs[0].(SelectionChangedMsg); !ok || msg.Cursor != 3 {
+		t.Fatalf("expected the selection to change to row 3, got %v", msgs[0])
+	}
+	if msg, ok := msgs[1].(ViewportChangedMsg); !ok || msg.Start != 1 {
+		t.Fatalf("expected the viewport to start at row 1, got %v", msgs[1])
+	}
+}
+
+func TestTruncateTolerance(t *testing.T) {
+	opts := []Option{
+		WithColumns([]Column{{Title: "Name", Width: 5}, {Title: "City", Width: 4, MaxWidth: 4}}),
+		WithRows([]Row{{"Alexis", "Rome"}, {"Bob", "Paris"}, {"Cy", "Oslo"}}),
+	}
+	td := func(model Model) tableData {
+		return tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+	}
+
+	model := New(opts...)
+	if got := td(model).At(0, 0); got != "Alex…" {
+		t.Fatalf("expected the name to be truncated by default, got %q", got)
+	}
+
+	model = New(append(opts, WithTruncateTolerance(1))...)
+	if got := td(model).At(0, 0); got != "Alexis" {
+		t.Fatalf("expected the name 1 cell over the width to be shown in full, got %q", got)
+	}
+	if got := td(model).At(1, 1); got != "Paris" {
+		t.Fatalf("expected the city 1 cell over the width to be shown in full, got %q", got)
+	}
+	if got := td(model).At(2, 0); got != "Cy    " {
+		t.Fatalf("expected the other names to be padded to the wider column, got %q", got)
+	}
+
+	model = New(WithColumns([]Column{{Title: "Name", Width: 4}}), WithRows([]Row{{"Alexis"}}), WithTruncateTolerance(1))
+	if got := td(model).At(0, 0); got != "Ale…" {
+		t.Fatalf("expected the name 2 cells over the width to be truncated, got %q", got)
+	}
+}
+
+func TestCursorUnderline(t *testing.T) {
+	opts := []Option{
+		WithColumns([]Column{{Title: "Name", Width: 6}, {Title: "City", Width: 6}}),
+		WithRows([]Row{{"Alice", "Rome"}, {"Bob", "Oslo"}}),
+	}
+	model := New(append(opts, WithCursorUnderline(true))...)
+	model.SetCursor(1)
+
+	for col := 0; col < 2; col++ {
+		style := model.columnStyle(1, col)
+		if !style.GetUnderline() {
+			t.Errorf("column %d: expected the selected row to be underlined", col)
+		}
+		if style.GetBold() {
+			t.Errorf("column %d: expected the selected style not to apply", col)
+		}
+		if model.columnStyle(0, col).GetUnderline() {
+			t.Errorf("column %d: expected the other rows not to be underlined", col)
+		}
+	}
+	if got, want := ansi.Strip(model.View()), ansi.Strip(New(opts...).View()); got != want {
+		t.Fatalf("expected the layout not to change, got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestColumnValues(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}, {Title: "City", Width: 6}}),
+		WithRows([]Row{{"Alice", "Rome"}, {"Bob"}, {"Cy", "Oslo"}}),
+	)
+	model.SortBy(0, true)
+
+	if got := fmt.Sprintf("%q", model.ColumnValues(1)); got != `["Rome" "" "Oslo"]` {
+		t.Fatalf("expected the values in row order, got %s", got)
+	}
+	if got := model.ColumnValues(-1); len(got) != 3 || got[0] != "" {
+		t.Fatalf("expected empty values out of bounds, got %q", got)
+	}
+}
+
+func TestGroupDividers(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Team", Width: 5}, {Title: "Name", Width: 5}}),
+		WithRows([]Row{{"red", "Cy"}, {"blue", "Alice"}, {"red", "Dee"}, {"blue", "Bob"}}),
+		WithGroupDividers(0),
+		WithCollapseDuplicates([]int{0}),
+	)
+	model.SortBy(0, false)
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	want := []string{
+		"│ blue  │ Alice │",
+		"│       │ Bob   │",
+		"│ ───── │ ───── │",
+		"│ red   │ Cy    │",
+		"│       │ Dee   │",
+	}
+	if got := strings.Join(lines[3:len(lines)-1], "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("expected\n%s\ngot\n%s", strings.Join(want, "\n"), got)
+	}
+
+	model.SetCursor(1)
+	model.MoveDown(1)
+	if got := model.SelectedRow()[1]; got != "Cy" {
+		t.Fatalf("expected the cursor to skip the divider, got %s", got)
+	}
+}
+
+func TestRowRenderedHeight(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}, {Title: "Description", Width: 10, Wrap: true}}),
+		WithRows([]Row{{"Alice", "quite long text"}, {"Bob", "short"}}),
+	)
+
+	if got := model.RowRenderedHeight(0); got != 2 {
+		t.Fatalf("expected the wrapped row to take 2 lines, got %d", got)
+	}
+	if got := model.RowRenderedHeight(1); got != 1 {
+		t.Fatalf("expected the simple row to take 1 line, got %d", got)
+	}
+	if got := model.RowRenderedHeight(2); got != 0 {
+		t.Fatalf("expected 0 for a row out of range, got %d", got)
+	}
+}
+
+func TestFilterCursorPolicy(t *testing.T) {
+	rows := []Row{{"apple"}, {"avocado"}, {"banana"}, {"blueberry"}, {"apricot"}}
+	newModel := func(opts ...Option) Model {
+		return New(append([]Option{
+			WithColumns([]Column{{Title: "Fruit", Width: 10}}),
+			WithRows(rows),
+			WithFiltering(true),
+		}, opts...)...)
+	}
+
+	model := newModel()
+	model.SetFilterQuery("b")
+	model.SetCursor(1)
+	model.SetFilterQuery("a")
+	if got := model.SelectedRow()[0]; got != "apple" {
+		t.Fatalf("expected the cursor to move to the first matching row, got %s", got)
+	}
+
+	model = newModel(WithFilterCursorPolicy(FilterCursorNearest))
+	model.SetFilterQuery("b")
+	model.SetCursor(1)
+	model.SetFilterQuery("ap")
+	if got := model.SelectedRow()[0]; got != "apricot" {
+		t.Fatalf("expected the cursor to move to the closest matching row, got %s", got)
+	}
+
+	model.SetFilterQuery("kiwi")
+	if model.Cursor() != 0 || model.SelectedRow() != nil {
+		t.Fatalf("expected the cursor at the top without matching rows, got %d", model.Cursor())
+	}
+}
+
+func TestAccountingFormat(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Item", Width: 6}, {Title: "Amount", Width: 8, Type: NumberColumn}}),
+		WithRows([]Row{{"rent", "-42"}, {"pay", "1200.50"}, {"gift", "n/a"}}),
+		WithAccountingFormat(1),
+	)
+	td := tableData{m: model, maxColumnWidths: model.getMaxColumnWidths()}
+
+	want := []string{"    (42)", " 1200.50", "     n/a"}
+	for i, w := range want {
+		if got := ansi.Strip(td.At(i, 1)); got != w {
+			t.Errorf("row %d: expected %q, got %q", i, w, got)
+		}
+	}
+	if got := model.Rows()[0][1]; got != "-42" {
+		t.Fatalf("expected the stored value to be intact, got %q", got)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	rows := []Row{{"Bob", "40"}, {"Alice", "9"}, {"Cy", "31"}, {"Dee", "2"}}
+	base := func() Model {
+		model := New(
+			WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Age", Width: 5, Type: NumberColumn}}),
+			WithRows(rows[:2]),
+			WithFiltering(true),
+		)
+		model.SortBy(1, false)
+		return model
+	}
+
+	individually := base()
+	individually.SetRows(rows)
+	individually.SetFilterQuery("e")
+	individually.SetHeight(2)
+	individually.SetStyles(MinimalTheme().Styles)
+	individually.SetCursor(1)
+
+	batched := base()
+	batched.Configure(func(m *Model) {
+		WithRows(rows)(m)
+		m.FilterInput.SetValue("e")
+		m.filterState = FilterApplied
+		WithHeight(2)(m)
+		WithStyles(MinimalTheme().Styles)(m)
+		m.cursor = 1
+	})
+
+	if got, want := batched.View(), individually.View(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+	if batched.Cursor() != individually.Cursor() || batched.start != individually.start {
+		t.Fatalf("expected cursor %d and start %d, got %d and %d",
+			individually.Cursor(), individually.start, batched.Cursor(), batched.start)
+	}
+	if !batched.KeyMap.ClearFilter.Enabled() {
+		t.Fatal("expected the key bindings to be updated")
+	}
+}
+
+func TestRowIcon(t *testing.T) {
+	rows := []Row{{"build", "ok"}, {"test", "failed"}, {"lint", "running"}}
+	icons := map[string]string{"ok": "✓", "failed": "✗", "running": "…"}
+	model := New(
+		WithColumns([]Column{{Title: "Job", Width: 5}, {Title: "Status", Width: 7}}),
+		WithRows(rows),
+		WithRowIcon(func(row int) string { return icons[rows[row][1]] }),
+	)
+
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	want := []string{
+		"╭───┬───────┬─────────╮",
+		"│   │ Job   │ Status  │",
+		"├───┼───────┼─────────┤",
+		"│ ✓ │ build │ ok      │",
+		"│ ✗ │ test  │ failed  │",
+		"│ … │ lint  │ running │",
+		"╰───┴───────┴─────────╯",
+	}
+	if got := strings.Join(lines, "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("expected\n%s\ngot\n%s", strings.Join(want, "\n"), got)
+	}
+	if got := model.SelectedRow(); len(got) != 2 || got[0] != "build" {
+		t.Fatalf("expected the icon not to be part of the selected row, got %v", got)
+	}
+}
+
+func TestMovementStep(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows([]Row{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}),
+		WithFocused(true),
+	)
+	model.SetMovementStep(3)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if model.Cursor() != 3 {
+		t.Fatalf("expected a down key to move by 3 rows, got %d", model.Cursor())
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if model.Cursor() != 4 {
+		t.Fatalf("expected the cursor to be clamped to the last row, got %d", model.Cursor())
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if model.Cursor() != 1 {
+		t.Fatalf("expected an up key to move by 3 rows, got %d", model.Cursor())
+	}
+}
+
+func TestTitleCount(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Alina"}}),
+		WithTitle("Users"),
+		WithTitleCount(true),
+		WithFiltering(true),
+	)
+	title := func() string {
+		return strings.TrimSpace(strings.Split(ansi.Strip(model.View()), "\n")[0])
+	}
+
+	if got := title(); got != "Users (3)" {
+		t.Fatalf("expected the title with the row count, got %q", got)
+	}
+	model.SetFilterQuery("ali")
+	if got := title(); got != "Users (2)" {
+		t.Fatalf("expected the count of the rows matching the filter, got %q", got)
+	}
+}
+
+func TestBeforeRender(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Len", Width: 4}}),
+		WithRows([]Row{{"Alice", ""}, {"Bob", ""}}),
+		WithBeforeRender(func(m *Model) {
+			rows := make([]Row, len(m.Rows()))
+			for i, row := range m.Rows() {
+				rows[i] = Row{row[0], fmt.Sprint(len(row[0]))}
+			}
+			m.SetRows(rows)
+		}),
+	)
+
+	view := ansi.Strip(model.View())
+	if !strings.Contains(view, "Alice    │ 5") || !strings.Contains(view, "Bob      │ 3") {
+		t.Fatalf("expected the derived column in the view, got:\n%s", view)
+	}
+	if got := model.Rows()[0][1]; got != "" {
+		t.Fatalf("expected the model to be left unchanged, got %q", got)
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "A", Width: 4}, {Title: "B", Width: 4}, {Title: "C", Width: 4}}),
+		WithRows([]Row{{"Overflowing", "", ""}, {"Overflowing", "", "x"}, {"Overflowing", "x", ""}}),
+		WithOverflow(true),
+		WithHeight(3),
+	)
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+
+	for i, want := range []string{
+		"│ Over │ flow │ ing  │",
+		"│ Over │ flo… │ x    │",
+		"│ Ove… │ x    │      │",
+	} {
+		if got := lines[3+i]; got != want {
+			t.Errorf("row %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestViewInBox(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 10}, {Title: "City", Width: 10}}),
+		WithRows([]Row{{"Alice", "Paris"}, {"Bob", "Rome"}, {"Carol", "Oslo"}}),
+		WithHeight(3),
+	)
+
+	for _, size := range [][2]int{{12, 4}, {40, 12}} {
+		width, height := size[0], size[1]
+		lines := strings.Split(model.ViewInBox(width, height), "\n")
+		if len(lines) != height {
+			t.Fatalf("%dx%d: expected %d lines, got %d", width, height, height, len(lines))
+		}
+		for i, line := range lines {
+			if w := ansi.StringWidth(line); w != width {
+				t.Errorf("%dx%d: expected line %d to be %d wide, got %d", width, height, i, width, w)
+			}
+		}
+	}
+}
+
+func TestCenterOnScroll(t *testing.T) {
+	rows := make([]Row, 20)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprint(i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "N", Width: 4}}),
+		WithRows(rows),
+		WithHeight(5),
+		WithCenterOnScroll(true),
+	)
+
+	model.MoveDown(4)
+	if model.start != 0 {
+		t.Fatalf("expected moving within the viewport not to scroll, got start %d", model.start)
+	}
+	model.MoveDown(1)
+	if model.cursor != 5 || model.start != 3 {
+		t.Fatalf("expected the cursor row 5 to be centered from row 3, got cursor %d start %d", model.cursor, model.start)
+	}
+	model.MoveUp(2)
+	if model.start != 3 {
+		t.Fatalf("expected moving within the viewport not to scroll, got start %d", model.start)
+	}
+	model.MoveUp(1)
+	if model.cursor != 2 || model.start != 0 {
+		t.Fatalf("expected the viewport to stop at the first row, got cursor %d start %d", model.cursor, model.start)
+	}
+	model.GotoBottom()
+	if model.start != 15 {
+		t.Fatalf("expected the viewport to stop at the last row, got start %d", model.start)
+	}
+}
+
+func TestColumnAbbrev(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "Quantity", Abbrev: "Qty", Width: 4},
+			{Title: "Description", Abbrev: "Description", Width: 5},
+			{Title: "Price", Abbrev: "P", Width: 5},
+		}),
+		WithRows([]Row{{"3", "Apple", "1.00"}}),
+	)
+
+	header := strings.Split(ansi.Strip(model.View()), "\n")[1]
+	if want := "│ Qty  │ Desc… │ Price │"; header != want {
+		t.Fatalf("expected %q, got %q", want, header)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	newModel := func() Model {
+		return New(
+			WithColumns([]Column{{Title: "Name", Width: 8}}),
+			WithRows(rows),
+			WithHeight(3),
+			WithFocused(true),
+			WithChangeMessages(true),
+		)
+	}
+	messages := func(cmd tea.Cmd) string {
+		var msgs []tea.Msg
+		if cmd != nil {
+			for _, cmd := range cmd().(tea.BatchMsg) {
+				if cmd != nil {
+					msgs = append(msgs, cmd())
+				}
+			}
+		}
+		return fmt.Sprint(msgs)
+	}
+
+	model := newModel()
+	cmd := model.Dispatch(ActionGotoBottom)
+	if model.Cursor() != 9 {
+		t.Fatalf("expected the cursor on the last row, got %d", model.Cursor())
+	}
+	_, keyCmd := newModel().Update(tea.KeyMsg{Type: tea.KeyEnd})
+	if got, want := messages(cmd), messages(keyCmd); got != want || got == "[]" {
+		t.Fatalf("expected the command of the key press %s, got %s", want, got)
+	}
+
+	model.Blur()
+	if model.Dispatch(ActionGotoTop); model.Cursor() != 9 {
+		t.Fatalf("expected the action to be ignored while blurred, got cursor %d", model.Cursor())
+	}
+}
+
+func TestFullWidthSelection(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	selected := lipgloss.NewStyle().Background(lipgloss.Color("4"))
+	model := New(
+		WithColumns([]Column{{Title: "A", Width: 4}, {Title: "B", Width: 4}, {Title: "C", Width: 4}}),
+		WithRows([]Row{{"a", "b", "c"}, {"d", "e", "f"}}),
+		WithStyles(Styles{Selected: selected, Cell: lipgloss.NewStyle().Padding(0, 1)}),
+		WithWidth(30),
+		WithFullWidthSelection(true),
+	)
+
+	line := strings.Split(model.View(), "\n")[3]
+	var styled int
+	open := selected.Render("x")[:strings.Index(selected.Render("x"), "x")]
+	for _, part := range strings.Split(line, open)[1:] {
+		text, _, _ := strings.Cut(part, "\x1b[0m")
+		styled += ansi.StringWidth(text)
+	}
+	if styled != 30 {
+		t.Fatalf("expected the selection to span the 30 cells of the table, got %d in %q", styled, line)
+	}
+}
+
+func TestHelpOmitsPagingInShortTables(t *testing.T) {
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprint(i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "N", Width: 4}}),
+		WithRows(rows[:3]),
+		WithHeight(5),
+	)
+	model.Help.ShowAll = true
+
+	if help := model.HelpView(); strings.Contains(help, "page") {
+		t.Fatalf("expected no paging keys in the help of a short table, got %q", help)
+	}
+	if model.ActionEnabled(ActionPageDown) {
+		t.Fatal("expected paging to be disabled in a short table")
+	}
+
+	model.SetRows(rows)
+	if help := model.HelpView(); !strings.Contains(help, "page down") {
+		t.Fatalf("expected the paging keys in the help of a long table, got %q", help)
+	}
+
+	t.Run("bindings disabled in the key map stay disabled", func(t *testing.T) {
+		model := New(
+			WithColumns([]Column{{Title: "N", Width: 4}}),
+			WithRows(rows[:3]),
+			WithHeight(5),
+		)
+		model.KeyMap.PageDown.SetEnabled(false)
+
+		model.SetRows(rows)
+		if model.KeyMap.PageDown.Enabled() || model.ActionEnabled(ActionPageDown) {
+			t.Fatal("expected page down to stay disabled")
+		}
+		if !model.ActionEnabled(ActionPageUp) {
+			t.Fatal("expected page up to be enabled in a long table")
+		}
+	})
+}
+
+func TestEachRow(t *testing.T) {
+	model := New(WithRows([]Row{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}))
+
+	var visited []string
+	model.EachRow(func(i int, r Row) bool {
+		visited = append(visited, fmt.Sprint(i, r[0]))
+		return len(visited) < 3
+	})
+	if got := fmt.Sprint(visited); got != "[0a 1b 2c]" {
+		t.Fatalf("expected the first 3 rows, got %s", got)
+	}
+}
+
+func TestResponsiveHide(t *testing.T) {
+	model := New(
+		WithColumns([]Column{
+			{Title: "Name", Width: 6, Priority: 2},
+			{Title: "Notes", Width: 6},
+			{Title: "City", Width: 6, Priority: 1},
+		}),
+		WithRows([]Row{{"Alice", "n/a", "Paris"}}),
+		WithResponsiveHide(true),
+	)
+	header := func() string {
+		return strings.Split(ansi.Strip(model.View()), "\n")[1]
+	}
+
+	model.SetWidth(26)
+	if got := header(); got != "│ Name   │ Notes  │ City   │" {
+		t.Fatalf("expected all the columns to fit, got %q", got)
+	}
+	model.SetWidth(20)
+	if got, want := header(), "│ Name     │ City    │"; got != want {
+		t.Fatalf("expected the lowest priority column to be hidden, want %q got %q", want, got)
+	}
+	model.SetWidth(12)
+	if got, want := header(), "│ Name       │"; got != want {
+		t.Fatalf("expected only the highest priority column, want %q got %q", want, got)
+	}
+
+	t.Run("navigation steps over hidden columns", func(t *testing.T) {
+		model := model
+		model.SetWidth(26)
+		model.SetColCursor(1)
+
+		model.SetWidth(20)
+		if got := model.ColCursor(); got != 2 {
+			t.Fatalf("expected the focus to move off the hidden column, got %d", got)
+		}
+		model.MoveLeft(1)
+		if got := model.ColCursor(); got != 0 {
+			t.Fatalf("expected MoveLeft to step over the hidden column, got %d", got)
+		}
+		model.MoveRight(1)
+		if got := model.ColCursor(); got != 2 {
+			t.Fatalf("expected MoveRight to step over the hidden column, got %d", got)
+		}
+		model.SetColCursor(1)
+		if got := model.ColCursor(); got != 0 {
+			t.Fatalf("expected SetColCursor to skip the hidden column leftwards, got %d", got)
+		}
+		model.SetColCursor(1)
+		if got := model.ColCursor(); got != 2 {
+			t.Fatalf("expected SetColCursor to skip the hidden column rightwards, got %d", got)
+		}
+
+		model.SetWidth(12)
+		if got := model.ColCursor(); got != 0 {
+			t.Fatalf("expected the focus on the only rendered column, got %d", got)
+		}
+	})
+}
+
+func TestDeltaColumns(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Host", Width: 6}, {Title: "Load", Width: 6}, {Title: "Mem", Width: 6}}),
+		WithRows([]Row{
+			{"web-1", "10", "1.5"},
+			{"web-2", "13", "1.25"},
+			{"web-3", "8", "1.5"},
+			{"web-4", "n/a", "2"},
+		}),
+		WithBaselineRow(0),
+		WithDeltaColumns(1, 2),
+	)
+
+	for i, want := range [][]string{
+		{"10", "1.5"},
+		{"+3", "-0.25"},
+		{"-2", "0.0"},
+		{"n/a", "+0.5"},
+	} {
+		if got := []string{model.cellValue(i, 1), model.cellValue(i, 2)}; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("row %d: expected %v, got %v", i, want, got)
+		}
+	}
+	if got := model.Rows()[1][1]; got != "13" {
+		t.Fatalf("expected the stored value to be unchanged, got %q", got)
+	}
+}
+
+func TestNavigationOverFilteredRows(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Alina"}, {"Carol"}, {"Alba"}, {"Dave"}}),
+		WithFiltering(true),
+	)
+	model.SetFilterQuery("al")
+
+	model.GotoBottom()
+	if model.Cursor() != 2 || model.SelectedIndex() != 4 {
+		t.Fatalf("expected the last matching row, got cursor %d index %d", model.Cursor(), model.SelectedIndex())
+	}
+	model.MoveDown(1)
+	if model.Cursor() != 2 {
+		t.Fatalf("expected the cursor to stay on the last matching row, got %d", model.Cursor())
+	}
+	model.MoveUp(1)
+	if got := model.SelectedRow()[0]; got != "Alina" {
+		t.Fatalf("expected the previous matching row, got %q", got)
+	}
+}
+
+func TestColumnsFromStruct(t *testing.T) {
+	type item struct {
+		Name     string
+		Price    float64 `table:"Price,width=8,align=right"`
+		Quantity int     `table:",width=3"`
+		Internal string  `table:"-"`
+		note     string
+	}
+
+	cols, err := ColumnsFromStruct(&item{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(cols), fmt.Sprint([]Column{
+		{Title: "Name"},
+		{Title: "Price", Width: 8, Alignment: lipgloss.Right},
+		{Title: "Quantity", Width: 3},
+	}); got != want {
+		t.Fatalf("expected columns %s, got %s", want, got)
+	}
+
+	row := RowFromStruct(item{Name: "Apple", Price: 1.5, Quantity: 3, Internal: "x", note: "y"})
+	if got := fmt.Sprint(row); got != "[Apple 1.5 3]" {
+		t.Fatalf("expected the values of the columns in order, got %s", got)
+	}
+
+	if _, err := ColumnsFromStruct(42); err == nil {
+		t.Fatal("expected an error for a value that is not a struct")
+	}
+	type invalid struct {
+		Name string `table:"Name,width=wide"`
+	}
+	if _, err := ColumnsFromStruct(invalid{}); err == nil {
+		t.Fatal("expected an error for an invalid width")
+	}
+}
+
+func TestColorScale(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Host", Width: 6}, {Title: "CPU", Width: 4}}),
+		WithRows([]Row{{"web-1", "95"}, {"web-2", "10"}, {"web-3", "90"}, {"web-4", "n/a"}}),
+		WithColorScale(1, func(v float64) lipgloss.Color {
+			if v >= 50 {
+				return lipgloss.Color("196")
+			}
+			return lipgloss.Color("42")
+		}),
+	)
+	foreground := func(row int) string {
+		return fmt.Sprint(model.columnStyle(row, 1).GetForeground())
+	}
+
+	if got := foreground(1); got != "42" {
+		t.Errorf("expected the low value to be colored 42, got %s", got)
+	}
+	if got := foreground(2); got != "196" {
+		t.Errorf("expected the high value to be colored 196, got %s", got)
+	}
+	if got, want := foreground(3), fmt.Sprint(model.styles.Cell.GetForeground()); got != want {
+		t.Errorf("expected a value that is not a number to keep the cell color %s, got %s", want, got)
+	}
+	if got, want := foreground(0), fmt.Sprint(model.styles.Selected.GetForeground()); got != want {
+		t.Errorf("expected the selected row to keep the selected color %s, got %s", want, got)
+	}
+}
+
+func TestResetView(t *testing.T) {
+	rows := []Row{{"Carol"}, {"Alice"}, {"Bob"}, {"Alina"}}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithFiltering(true),
+		WithFocused(true),
+	)
+	resetKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")}
+	model.GotoBottom()
+	if model, _ = model.Update(resetKey); model.Cursor() != 3 || model.ActionEnabled(ActionResetView) {
+		t.Fatalf("expected the binding to be disabled without a sort or a filter, got cursor %d", model.Cursor())
+	}
+
+	model.SortBy(0, true)
+	model.SetFilterQuery("al")
+	model.GotoBottom()
+
+	model, _ = model.Update(resetKey)
+	if _, _, sorted := model.SortState(); sorted || model.FilterState() != Unfiltered {
+		t.Fatalf("expected the sort and the filter to be cleared, got sorted %t, %s", sorted, model.FilterState())
+	}
+	if model.Cursor() != 0 || model.SelectedRow()[0] != "Carol" {
+		t.Fatalf("expected the cursor on the first row, got %d %v", model.Cursor(), model.SelectedRow())
+	}
+	if got := fmt.Sprint(model.Rows()); got != fmt.Sprint(rows) || model.RowCount() != 4 {
+		t.Fatalf("expected the rows to be kept, got %s", got)
+	}
+	if model.ActionEnabled(ActionResetView) {
+		t.Fatal("expected the binding to be disabled once the view is reset")
+	}
+}
+
+func TestSortKeepsKeyedRowSelected(t *testing.T) {
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("host-%d", i), fmt.Sprint(i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Host", Width: 8}, {Title: "Load", Width: 4, Type: NumberColumn}}),
+		WithRows(rows),
+		WithHeight(3),
+		WithRowKey(func(r Row) string { return r[0] }),
+	)
+	model.SetCursor(8)
+	model.SortBy(1, true)
+	if got := model.SelectedRow()[0]; got != "host-8" || model.Cursor() != 1 || model.start > 1 {
+		t.Fatalf("expected host-8 to stay selected and visible, got %s at %d from %d", got, model.Cursor(), model.start)
+	}
+
+	updated := make([]Row, len(rows))
+	for i, row := range rows {
+		updated[len(rows)-1-i] = Row{row[0], fmt.Sprint(len(rows) - i)}
+	}
+	model.SetRows(updated)
+	if got := model.SelectedRow()[0]; got != "host-8" || model.Cursor() != 8 || model.start > 8 || model.start+3 <= 8 {
+		t.Fatalf("expected host-8 to stay selected and visible after the rows were sorted again, got %s at %d from %d",
+			got, model.Cursor(), model.start)
+	}
+}
+
+func TestHeaderView(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "City", Width: 8}}),
+		WithRows([]Row{{"Alice", "Paris"}, {"Bob", "Rome"}}),
+		WithVerticalHeaders(true),
+	)
+	model.SortBy(0, true)
+
+	header := model.HeaderView()
+	table := model.View()
+	if lines := strings.Count(header, "\n") + 1; lines != model.headerHeight() {
+		t.Fatalf("expected %d header lines, got %d:\n%s", model.headerHeight(), lines, header)
+	}
+	if !strings.HasPrefix(table, header+"\n") {
+		t.Fatalf("expected the header of the view, got:\n%s\nfor:\n%s", header, table)
+	}
+	if !strings.Contains(header, "▼") {
+		t.Fatalf("expected the sort indicator in the header, got:\n%s", header)
+	}
+}
+
+func TestBodyView(t *testing.T) {
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i), "a\nb"}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Notes", Width: 8, Wrap: true}}),
+		WithRows(rows),
+		WithHeight(5),
+		WithExpandableRows(true),
+	)
+	model.ToggleExpanded(5)
+	model.SetCursor(5)
+
+	view := strings.Split(model.View(), "\n")
+	data := strings.Join(view[:len(view)-1], "\n")
+	if got := model.HeaderView() + "\n" + model.BodyView(); got != data {
+		t.Fatalf("expected the header and the body to make the view, got:\n%s\nwant:\n%s", got, data)
+	}
+	if body := ansi.Strip(model.BodyView()); !strings.Contains(body, "row 5") || strings.Contains(body, "row 0") {
+		t.Fatalf("expected the scrolled rows, got:\n%s", body)
+	}
+}
+
+func TestRowHomeEnd(t *testing.T) {
+	cols := make([]Column, 8)
+	row := make(Row, len(cols))
+	for i := range cols {
+		cols[i] = Column{Title: fmt.Sprintf("Col %d", i), Width: 6}
+		row[i] = fmt.Sprint(i)
+	}
+	model := New(
+		WithColumns(cols),
+		WithRows([]Row{row}),
+		WithWidth(30),
+		WithHorizontalScroll(true),
+		WithFocused(true),
+	)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("$")})
+	if model.ColCursor() != 7 {
+		t.Fatalf("expected the last column to be focused, got %d", model.ColCursor())
+	}
+	if header := strings.Split(ansi.Strip(model.View()), "\n")[1]; !strings.Contains(header, "Col 7") || strings.Contains(header, "Col 0") {
+		t.Fatalf("expected the last column to be scrolled into view, got %q", header)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0")})
+	if header := strings.Split(ansi.Strip(model.View()), "\n")[1]; model.ColCursor() != 0 || !strings.Contains(header, "Col 0") {
+		t.Fatalf("expected the first column to be focused and in view, got %d %q", model.ColCursor(), header)
+	}
+}
+
+func TestSortedAndFilteredRows(t *testing.T) {
+	rows := []Row{{"Alice", "3"}, {"Bob", "1"}, {"Alina", "1"}, {"Carol", "2"}, {"Alba", "2"}}
+	visible := func(m Model) string {
+		var names []string
+		for i := 0; i < m.numRows(); i++ {
+			names = append(names, fmt.Sprint(m.rowIndex(i), m.rows[m.rowIndex(i)][0]))
+		}
+		return fmt.Sprint(names)
+	}
+	want := "[2Alina 4Alba 0Alice]"
+
+	sortFirst := New(WithColumns([]Column{{Title: "Name"}, {Title: "Rank", Type: NumberColumn}}), WithRows(rows), WithFiltering(true))
+	sortFirst.SortBy(1, false)
+	sortFirst.SetFilterQuery("al")
+	if got := visible(sortFirst); got != want {
+		t.Fatalf("sorting then filtering: expected %s, got %s", want, got)
+	}
+
+	filterFirst := New(WithColumns([]Column{{Title: "Name"}, {Title: "Rank", Type: NumberColumn}}), WithRows(rows), WithFiltering(true))
+	filterFirst.SetFilterQuery("al")
+	filterFirst.SetCursor(2)
+	filterFirst.SortBy(1, false)
+	if got := visible(filterFirst); got != want {
+		t.Fatalf("filtering then sorting: expected %s, got %s", want, got)
+	}
+	if filterFirst.Cursor() != 1 || filterFirst.SelectedIndex() != 4 {
+		t.Fatalf("expected the cursor to follow Alba, got cursor %d index %d", filterFirst.Cursor(), filterFirst.SelectedIndex())
+	}
+}
+
+func TestIndexMapping(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name"}, {Title: "Rank", Type: NumberColumn}}),
+		WithRows([]Row{{"Alice", "3"}, {"Bob", "1"}, {"Alina", "1"}, {"Carol", "2"}, {"Alba", "2"}}),
+		WithFiltering(true),
+	)
+	model.SetFilterQuery("al")
+	model.SortBy(1, true)
+
+	for visible, want := range []int{0, 4, 2} {
+		underlying, ok := model.UnderlyingIndex(visible)
+		if !ok || underlying != want {
+			t.Fatalf("expected visible row %d to be row %d, got %d %t", visible, want, underlying, ok)
+		}
+		if back, ok := model.VisibleIndex(underlying); !ok || back != visible {
+			t.Fatalf("expected row %d to be visible row %d, got %d %t", underlying, visible, back, ok)
+		}
+	}
+	if _, ok := model.VisibleIndex(1); ok {
+		t.Fatal("expected a row hidden by the filter not to be visible")
+	}
+	if _, ok := model.UnderlyingIndex(3); ok {
+		t.Fatal("expected no visible row past the last one")
+	}
+}
+
+func TestSetRowsShrinkingWhileScrolled(t *testing.T) {
+	rows := make([]Row, 10)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i), fmt.Sprint(i)}
+	}
+	for name, opts := range map[string][]Option{
+		"horizontal scroll": {WithHorizontalScroll(true)},
+		"width fill":        {WithWidthFill(FillRight), WithWidth(30)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			model := New(append([]Option{
+				WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "N", Width: 4}}),
+				WithRows(rows),
+				WithHeight(3),
+				WithDiffHighlight(true),
+			}, opts...)...)
+			model.SortBy(1, false)
+			model.GotoBottom()
+
+			model.SetRows(rows[:2])
+			if model.Cursor() != 1 || model.start != 0 {
+				t.Fatalf("expected the cursor on the last row and both rows in view, got cursor %d start %d", model.Cursor(), model.start)
+			}
+			view := ansi.Strip(model.View())
+			if !strings.Contains(view, "row 0") || !strings.Contains(view, "row 1") {
+				t.Fatalf("expected the remaining rows, got:\n%s", view)
+			}
+		})
+	}
+
+	t.Run("fewer rows than the height", func(t *testing.T) {
+		rows := make([]Row, 30)
+		for i := range rows {
+			rows[i] = Row{fmt.Sprintf("row %d", i)}
+		}
+		model := New(
+			WithColumns([]Column{{Title: "Name", Width: 8}}),
+			WithRows(rows),
+			WithHeight(5),
+		)
+		model.GotoBottom()
+
+		model.SetRows(rows[:12])
+		if model.Cursor() != 11 || model.start != 7 {
+			t.Fatalf("expected the last page to be in view, got cursor %d start %d", model.Cursor(), model.start)
+		}
+	})
+}
+
+func TestFilterKeepsViewportFull(t *testing.T) {
+	rows := make([]Row, 30)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprintf("row %d", i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows(rows),
+		WithHeight(5),
+		WithFiltering(true),
+	)
+	model.GotoBottom()
+
+	model.SetFilterQuery("2")
+	if model.numRows() != 12 || model.Cursor() != 11 {
+		t.Fatalf("expected the cursor on the last of 12 matches, got cursor %d of %d", model.Cursor(), model.numRows())
+	}
+	if got := strings.Count(ansi.Strip(model.BodyView()), "row "); got != 5 {
+		t.Fatalf("expected 5 rows in view, got %d:\n%s", got, model.BodyView())
+	}
+}
+
+func TestCenterOnScrollWithoutMatches(t *testing.T) {
+	rows := make([]Row, 20)
+	for i := range rows {
+		rows[i] = Row{fmt.Sprint(i)}
+	}
+	model := New(
+		WithColumns([]Column{{Title: "N", Width: 4}}),
+		WithRows(rows),
+		WithHeight(3),
+		WithFiltering(true),
+		WithCenterOnScroll(true),
+	)
+	model.GotoBottom()
+
+	model.SetFilterQuery("zzz")
+	if model.RowCount() != 0 || model.Cursor() != 0 || model.start != 0 {
+		t.Fatalf("expected no rows in view, got %d rows, cursor %d start %d", model.RowCount(), model.Cursor(), model.start)
+	}
+	model.SetFilterQuery("")
+	if model.RowCount() != 20 {
+		t.Fatalf("expected the rows back, got %d", model.RowCount())
+	}
+}
+
+func TestColumnCursorClampedWhenColumnsShrink(t *testing.T) {
+	model := New(
+		WithRows([]Row{{"aaaaaa", "bbbbbbb", "ccccccc"}}),
+		WithWidth(12),
+		WithHorizontalScroll(true),
+	)
+	model.MoveRight(2)
+
+	model.SetRows([]Row{{"a"}})
+	if model.ColCursor() != 0 || model.colStart != 0 {
+		t.Fatalf("expected the column cursor on the only column, got %d from %d", model.ColCursor(), model.colStart)
+	}
+	if view := ansi.Strip(model.View()); !strings.Contains(view, "a") {
+		t.Fatalf("expected the remaining column, got:\n%s", view)
+	}
+
+	model.SetRows([]Row{{"aaaaaa", "bbbbbbb", "ccccccc"}})
+	model.MoveRight(2)
+	WithColumns([]Column{{Title: "A", Width: 6}})(&model)
+	if model.ColCursor() != 0 || model.colStart != 0 {
+		t.Fatalf("expected the column cursor on the only column, got %d from %d", model.ColCursor(), model.colStart)
+	}
+	model.View()
+}
+
+func TestMouseClickWithTitle(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 6}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}, {"Carol"}}),
+		WithTitle("Users"),
+		WithFocused(true),
+	)
+	model.SetCursor(2)
+
+	// Users
+	// ╭────────╮
+	// │ Name   │
+	// ├────────┤
+	// │ Alice  │
+	lines := strings.Split(ansi.Strip(model.View()), "\n")
+	if !strings.Contains(lines[4], "Alice") {
+		t.Fatalf("expected the first row on the fifth line, got:\n%s", strings.Join(lines, "\n"))
+	}
+	model, _ = model.Update(tea.MouseMsg{X: 3, Y: 4, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if model.Cursor() != 0 {
+		t.Fatalf("expected clicking the first row to select it, got %d", model.Cursor())
+	}
+}
+
+func TestMouseClickWithRowTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("row").Parse("{{.Name}} is {{.Age}}"))
+	var clicked []string
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}, {Title: "Age", Width: 5}}),
+		WithRows([]Row{{"Alice", "30"}, {"Bob", "4"}, {"Carol", "52"}}),
+		WithRowTemplate(tmpl, []string{"Name", "Age"}),
+		WithStyles(Styles{}),
+		WithFocused(true),
+		WithCellClick(func(row, col int) tea.Cmd {
+			clicked = append(clicked, fmt.Sprint(row, col))
+			return nil
+		}),
+	)
+
+	// Alice is 30
+	// Bob is 4
+	// Carol is 52
+	model, _ = model.Update(tea.MouseMsg{X: 10, Y: 2, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if model.Cursor() != 2 {
+		t.Fatalf("expected clicking the third line to select its row, got %d", model.Cursor())
+	}
+	model, _ = model.Update(tea.MouseMsg{X: 11, Y: 0, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	model, _ = model.Update(tea.MouseMsg{X: 0, Y: 3, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if model.Cursor() != 2 {
+		t.Fatalf("expected clicks past the lines to be ignored, got %d", model.Cursor())
+	}
+	if got := fmt.Sprint(clicked); got != "[2 0]" {
+		t.Fatalf("expected a click on the first cell of the third row, got %s", got)
+	}
+}
+
+func TestFilterDebounceUpdatesBindings(t *testing.T) {
+	model := New(
+		WithColumns([]Column{{Title: "Name", Width: 8}}),
+		WithRows([]Row{{"Alice"}, {"Bob"}}),
+		WithFiltering(true),
+		WithFilterDebounce(time.Second),
+		WithFocused(true),
+	)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if model.ActionEnabled(ActionAcceptFilter) {
+		t.Fatal("expected accepting an empty query to be disabled")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if !model.ActionEnabled(ActionAcceptFilter) {
+		t.Fatal("expected accepting the query to be enabled while the filter is pending")
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if model.ActionEnabled(ActionAcceptFilter) {
+		t.Fatal("expected accepting the emptied query to be disabled while the filter is pending")
+	}
+}
@@ -0,0 +1,82 @@
+package table
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// WithRowTemplate renders each row as a single line executing tmpl instead of
+// as columns, like a list. The template is executed with a map from the given
+// field names to the values of the columns at the same index, so that
+// fieldNames {"Name", "Age"} make the first cell available as {{.Name}} and the
+// second one as {{.Age}}. The lines span the width of the table and are styled
+// like the cells of the first column, without headers or borders. A row the
+// template fails to render shows the error instead. Clicking a line counts as
+// clicking the first cell of its row. nil renders columns again.
+func WithRowTemplate(tmpl *template.Template, fieldNames []string) Option {
+	return func(m *Model) {
+		m.rowTemplate = tmpl
+		m.templateFields = fieldNames
+	}
+}
+
+// templateView renders the visible rows with the row template.
+func (m Model) templateView() string {
+	plan := m.renderPlan()
+	lines := make([]string, len(plan))
+	for i, p := range plan {
+		switch p.kind {
+		case dataRow:
+			lines[i] = m.templateValue(p.row)
+		case separatorRow:
+			lines[i] = p.label
+		}
+	}
+	width := m.manualWidth
+	if width == 0 {
+		for _, line := range lines {
+			width = max(width, ansi.StringWidth(line))
+		}
+	}
+
+	for i, p := range plan {
+		style := m.styles.Filler
+		switch p.kind {
+		case dataRow:
+			style = m.columnStyle(p.row, 0)
+		case separatorRow:
+			style = m.styles.Separator
+		case skeletonRow:
+			style = m.styles.Skeleton
+		case groupDividerRow:
+			style = m.styles.GroupDivider
+		}
+		content := max(0, width-style.GetHorizontalFrameSize())
+		switch p.kind {
+		case skeletonRow:
+			lines[i] = strings.Repeat(skeletonBar, content)
+		case groupDividerRow:
+			lines[i] = separatorValue("", false, content)
+		}
+		line := ansi.Truncate(lines[i], content, "…")
+		lines[i] = style.Render(lipgloss.PlaceHorizontal(content, lipgloss.Left, line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// templateValue returns the visible row at index i rendered with the row
+// template, on a single line.
+func (m Model) templateValue(i int) string {
+	fields := make(map[string]string, len(m.templateFields))
+	for col, name := range m.templateFields {
+		fields[name] = m.transformedValue(i, col)
+	}
+	var b strings.Builder
+	if err := m.rowTemplate.Execute(&b, fields); err != nil {
+		return err.Error()
+	}
+	return strings.ReplaceAll(b.String(), "\n", " ")
+}
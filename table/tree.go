@@ -0,0 +1,109 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TreeConfig configures how a table renders hierarchical rows. Rows are
+// stored depth first: the children of a row follow it with a greater depth.
+type TreeConfig struct {
+	// Depth returns the depth of the row at the given index in rows, 0 for
+	// the top level rows. nil for all rows to be at the top level.
+	Depth func(index int) int
+	// Indent is the number of spaces per level of depth. 2 if 0.
+	Indent int
+	// Expanded and Collapsed are rendered before rows with children. "▾ " and
+	// "▸ " if empty. Rows without children are padded to the same width.
+	Expanded  string
+	Collapsed string
+}
+
+// WithTree renders the rows as a tree: the first column is indented by the
+// depth of each row, with a caret before the rows with children. The children
+// of a row are hidden until the row is expanded, with Expand or the
+// ToggleExpand key binding. The filter only matches the rows that are not
+// hidden: the children of a collapsed row stay hidden even when they match.
+func WithTree(config TreeConfig) Option {
+	return func(m *Model) {
+		if config.Indent == 0 {
+			config.Indent = 2
+		}
+		if config.Expanded == "" {
+			config.Expanded = "▾ "
+		}
+		if config.Collapsed == "" {
+			config.Collapsed = "▸ "
+		}
+		m.tree = &config
+		m.updateTreeRows()
+		m.updateKeybindings()
+	}
+}
+
+// updateTreeRows shows or hides the rows after a row is expanded or
+// collapsed, keeping the cursor on the selected row.
+func (m *Model) updateTreeRows() {
+	if m.tree == nil {
+		m.onResize()
+		return
+	}
+	selected := m.SelectedIndex()
+	m.updateVisibleRows()
+	m.restoreCursor(selected)
+}
+
+// collapsedTreeRows returns the indexes in rows of the rows hidden because an
+// ancestor is collapsed.
+func (m Model) collapsedTreeRows() map[int]bool {
+	if m.tree == nil {
+		return nil
+	}
+	hidden := map[int]bool{}
+	collapsed := -1 // depth of the collapsed ancestor, -1 if none.
+	for i := range m.rows {
+		depth := m.treeDepth(i)
+		if collapsed >= 0 && depth > collapsed {
+			hidden[i] = true
+			continue
+		}
+		collapsed = -1
+		if m.hasChildren(i) && !m.expanded[i] {
+			collapsed = depth
+		}
+	}
+	return hidden
+}
+
+func (m Model) treeDepth(index int) int {
+	if m.tree == nil || m.tree.Depth == nil {
+		return 0
+	}
+	return max(0, m.tree.Depth(index))
+}
+
+// hasChildren returns whether the row at the given index in rows has children.
+func (m Model) hasChildren(index int) bool {
+	return index+1 < len(m.rows) && m.treeDepth(index+1) > m.treeDepth(index)
+}
+
+// treePrefix returns the indentation and caret rendered before the first cell
+// of the row at the given index in rows.
+func (m Model) treePrefix(index int) string {
+	var caret string
+	switch {
+	case !m.hasChildren(index):
+	case m.expanded[index]:
+		caret = m.tree.Expanded
+	default:
+		caret = m.tree.Collapsed
+	}
+	width := max(lipgloss.Width(m.tree.Expanded), lipgloss.Width(m.tree.Collapsed))
+	indent := strings.Repeat(" ", m.treeDepth(index)*m.tree.Indent)
+	return indent + caret + strings.Repeat(" ", width-lipgloss.Width(caret))
+}
+
+func (m Model) treePrefixWidth(index int) int {
+	return lipgloss.Width(m.treePrefix(index))
+}
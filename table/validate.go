@@ -0,0 +1,41 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks the table for misconfigurations, such as rows that do not
+// have one value per column, negative column widths or a cursor out of range,
+// and returns an error describing all of them, or nil. It is meant to catch
+// mistakes during development: the table renders regardless.
+func (m Model) Validate() error {
+	var errs []error
+	for i, col := range m.cols {
+		switch {
+		case col.Width < 0:
+			errs = append(errs, fmt.Errorf("column %d (%q) has a negative width: %d", i, col.Title, col.Width))
+		case col.MinWidth < 0:
+			errs = append(errs, fmt.Errorf("column %d (%q) has a negative minimum width: %d", i, col.Title, col.MinWidth))
+		case col.MaxWidth < 0:
+			errs = append(errs, fmt.Errorf("column %d (%q) has a negative maximum width: %d", i, col.Title, col.MaxWidth))
+		case col.MaxWidth > 0 && col.MinWidth > col.MaxWidth:
+			errs = append(errs, fmt.Errorf("column %d (%q) has a minimum width of %d above its maximum width of %d",
+				i, col.Title, col.MinWidth, col.MaxWidth))
+		}
+	}
+	if len(m.cols) > 0 {
+		for i, row := range m.rows {
+			if len(row) != len(m.cols) {
+				errs = append(errs, fmt.Errorf("row %d has %d values, want one per column: %d", i, len(row), len(m.cols)))
+			}
+		}
+	}
+	if n := m.numRows(); n > 0 && (m.cursor < 0 || m.cursor >= n) {
+		errs = append(errs, fmt.Errorf("cursor %d is out of range: %d rows", m.cursor, n))
+	}
+	if n := m.numRows(); m.start < 0 || (n > 0 && m.start >= n) {
+		errs = append(errs, fmt.Errorf("first visible row %d is out of range: %d rows", m.start, n))
+	}
+	return errors.Join(errs...)
+}